@@ -0,0 +1,66 @@
+package clis
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// getPublishedChecksum retrieves a checksum file (either a bare checksum or lines of "<checksum>  <filename>")
+// and returns the sha256 checksum listed for the file
+func getPublishedChecksum(ctx context.Context, checksumUrl string, filename string) (string, error) {
+	resp, err := httpGet(ctx, checksumUrl)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving checksum from url: %s, %s", resp.Status, checksumUrl)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	for lines.Scan() {
+		fields := strings.Fields(lines.Text())
+
+		if len(fields) == 1 {
+			return fields[0], nil
+		}
+
+		if len(fields) >= 2 && strings.TrimPrefix(fields[1], "*") == filename {
+			return fields[0], nil
+		}
+	}
+
+	if err := lines.Err(); err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("unable to find checksum for %s in %s", filename, checksumUrl)
+}
+
+// getGitHubReleaseChecksum returns the checksum of the release asset listed in the checksums file published with the release
+func getGitHubReleaseChecksum(ctx context.Context, org string, repo string, tag string, checksumsFile string, assetName string) (string, error) {
+	checksumUrl := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", org, repo, tag, checksumsFile)
+
+	return getPublishedChecksum(ctx, checksumUrl, assetName)
+}
+
+func verifyChecksum(url string, data []byte, expectedChecksum string) error {
+	checksum := sha256.Sum256(data)
+	actualChecksum := hex.EncodeToString(checksum[:])
+
+	if !strings.EqualFold(actualChecksum, expectedChecksum) {
+		return fmt.Errorf("checksum of download does not match the published checksum: %s, %s != %s", url, actualChecksum, expectedChecksum)
+	}
+
+	return nil
+}
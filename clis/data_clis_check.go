@@ -2,13 +2,17 @@ package clis
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/bzip2"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
@@ -16,6 +20,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -23,11 +29,49 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-var versionedInstallRe = regexp.MustCompile("([a-z-]+)-([0-9]+[.]?[0-9]*[.]?[0-9]*)")
+// executableMagicBytes are the headers of ELF, Mach-O (32 and 64 bit, either byte order) and universal Mach-O files
+var executableMagicBytes = [][]byte{
+	{0x7f, 'E', 'L', 'F'},
+	{0xfe, 0xed, 0xfa, 0xce},
+	{0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe},
+	{0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+}
+
+// versionedInstallRe splits a cli name and version (e.g. argocd-2.11.0-rc1 or s2i-1.3.0+build.1). The name may
+// contain digits and hyphens and the version may include pre-release and build metadata suffixes
+var versionedInstallRe = regexp.MustCompile(`^([a-z][a-z0-9-]*?)-([0-9]+(?:[.][0-9]+)*(?:-[0-9A-Za-z.-]+)?(?:[+][0-9A-Za-z.-]+)?)$`)
 var fullVersionRe = regexp.MustCompile("[0-9][.][0-9]+[.][0-9]+")
+var helmVersionRe = regexp.MustCompile(`Version:"v?([^"]+)"`)
+var ocVersionRe = regexp.MustCompile(`Client Version:\s*v?([0-9][^\s]*)`)
+var kubectlVersionRe = regexp.MustCompile(`Client Version:\s*(?:version[.]Info\{.*GitVersion:")?v?([0-9][^\s",]*)`)
+var dottedVersionRe = regexp.MustCompile(`[0-9]+(?:[.][0-9]+)+`)
+var singleNumberVersionRe = regexp.MustCompile(`[0-9]+`)
+var ibmcloudPluginVersionRe = regexp.MustCompile(`Plugin Version\s+([0-9][^\s]*)`)
+var releaseAssetSkipRe = regexp.MustCompile(`([.](sha256|sha256sum|sha512|md5|sig|asc|pem|sbom|txt|json)|checksums.*)$`)
+var kubectlReleaseRe = regexp.MustCompile(`^v1[.][0-9]+[.][0-9]+$`)
+
+const gitHubReleasesPerPage = 100
+
+// maxGitHubReleasePages limits the number of pages of releases searched for a tag prefix
+const maxGitHubReleasePages = 5
+
+const kubectlStableUrl = "https://dl.k8s.io/release/stable.txt"
+
+// kubectlStableTimeout bounds the lookup of the stable kubectl release, including retries
+const kubectlStableTimeout = 30 * time.Second
 
 type GitHubRelease struct {
-	TagName string `json:"tag_name"`
+	TagName    string               `json:"tag_name"`
+	Draft      bool                 `json:"draft"`
+	Prerelease bool                 `json:"prerelease"`
+	Assets     []GitHubReleaseAsset `json:"assets"`
+}
+
+type GitHubReleaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadUrl string `json:"browser_download_url"`
 }
 
 func dataClisCheck() *schema.Resource {
@@ -40,50 +84,166 @@ func dataClisCheck() *schema.Resource {
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Description: "The list of clis that should be installed. Should be any of: jq, igc, yq, helm, argocd, rosa, kubeseal, oc, kustomize, ibmcloud, ibmcloud-is, ibmcloud-ob, ibmcloud-ks, ibmcloud-cr, gitu, gh, glab, openshift-install",
+				Description: "The list of clis that should be installed. Should be any of: " + strings.Join(supportedCliNames(), ", ") + ". A version can be requested with name@version (e.g. operator-sdk@1.34.1) or name-version. The clis that a cli depends on (e.g. ibmcloud for the ibmcloud plugins) are installed first.",
+			},
+			"install": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Flag indicating that missing clis should be installed. When false the clis are only checked and an error is reported for any that are missing or below the required version. Nothing is downloaded or written to bin_dir.",
+			},
+			"install_defaults": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Flag indicating that the default clis from the provider default_clis config (yq, jq, igc, kubeseal, oc, kubectl unless configured) should be installed in addition to the requested clis.",
+			},
+			"tool_versions_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path to an asdf .tool-versions file. The versions listed in the file are used for any of the clis that have not been given an explicit version.",
+			},
+			"no_path_modification": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Flag indicating that bin_dir should not be added to the PATH of the provider process. Use when the installed clis are referenced by their absolute path so that bin_dir doesn't shadow the system clis for the rest of the run.",
+			},
+			"manifest_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path where a JSON manifest describing the installed clis should be written. For each cli the manifest records whether it was installed or already present, its version, path and source url.",
 			},
 			"bin_dir": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "The directory where the clis have been installed from the provider bin_dir config.",
 			},
+			"ibmcloud_home": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The directory used as IBMCLOUD_HOME when installing the ibmcloud plugins. Set IBMCLOUD_HOME to this value to use the installed plugins.",
+			},
+			"installed": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeBool,
+				},
+				Description: "Map of cli name to a flag indicating that the cli was installed during this read. The flag is false for clis that were already present.",
+			},
+			"versions": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Map of cli name to the version of the cli that is available. The versions are included in the id so that a change to the latest release of an unpinned cli shows up in the plan.",
+			},
 		},
 	}
 }
 
-var installers map[string]func(ctx2 context.Context, binDir string, envContext EnvContext, version string) (bool, error)
+// CliInstaller describes how a cli is installed and how the version of an existing copy is probed. Present
+// is only needed when the installer provides something other than a single binary named after the cli
+type CliInstaller struct {
+	Setup       func(ctx2 context.Context, binDir string, envContext EnvContext, version string) (bool, error)
+	Present     func(ctx2 context.Context, binDir string, version string) bool
+	VersionArgs []string
+	// VersionRe selects the version of the cli from the output of the version command when the output contains
+	// several version numbers. The first submatch holds the version
+	VersionRe *regexp.Regexp
+	// FreeBSD indicates that a freebsd build of the cli is published
+	FreeBSD bool
+	// Dependencies are the clis that must be installed before this one (e.g. ibmcloud for the ibmcloud plugins)
+	Dependencies []string
+	// NoLatestRelease indicates that the installer doesn't look up the latest release when no version is requested
+	// (e.g. yq installs fixed versions and the plugins are installed with their parent cli), so no warning is given
+	NoLatestRelease bool
+	// Binary is the name of the executable installed into bin_dir when it differs from the cli name
+	// (e.g. kubectl-argo-rollouts for argo-rollouts)
+	Binary string
+}
+
+var installers map[string]CliInstaller
 var defaultVersions map[string]string
 
-func getInstallers() map[string]func(ctx2 context.Context, binDir string, envContext EnvContext, version string) (bool, error) {
+func getInstallers() map[string]CliInstaller {
 	if installers != nil {
 		return installers
 	}
 
-	installers = make(map[string]func(ctx2 context.Context, binDir string, envContext EnvContext, version string) (bool, error))
-
-	installers["jq"] = setupJq
-	installers["igc"] = setupIgc
-	installers["yq"] = setupYq
-	installers["helm"] = setupHelm
-	installers["argocd"] = setupArgoCD
-	installers["rosa"] = setupRosa
-	installers["kubeseal"] = setupKubeseal
-	installers["oc"] = setupKube
-	installers["kustomize"] = setupKustomize
-	installers["ibmcloud"] = setupIBMCloud
-	installers["ibmcloud-is"] = setupIBMCloudISPlugin
-	installers["ibmcloud-ob"] = setupIBMCloudOBPlugin
-	installers["ibmcloud-ks"] = setupIBMCloudKSPlugin
-	installers["ibmcloud-cr"] = setupIBMCloudCRPlugin
-	installers["gitu"] = setupGitu
-	installers["gh"] = setupGh
-	installers["glab"] = setupGlab
-	installers["openshift-install"] = setupOpenShiftInstall
-	installers["operator-sdk"] = setupOperatorSdk
+	installers = make(map[string]CliInstaller)
+
+	installers["jq"] = CliInstaller{Setup: setupJq}
+	installers["igc"] = CliInstaller{Setup: setupIgc}
+	installers["yq"] = CliInstaller{Setup: setupYq, Present: yqPresent, FreeBSD: true, NoLatestRelease: true}
+	installers["helm"] = CliInstaller{Setup: setupHelm, VersionArgs: []string{"version"}, VersionRe: helmVersionRe}
+	installers["argocd"] = CliInstaller{Setup: setupArgoCD, VersionArgs: []string{"version", "--client"}}
+	installers["rosa"] = CliInstaller{Setup: setupRosa, VersionArgs: []string{"version"}}
+	installers["kubeseal"] = CliInstaller{Setup: setupKubeseal}
+	installers["oc"] = CliInstaller{Setup: setupOc, VersionArgs: []string{"version", "--client"}, VersionRe: ocVersionRe}
+	installers["kubectl"] = CliInstaller{Setup: setupKubectl, VersionArgs: []string{"version", "--client"}, VersionRe: kubectlVersionRe}
+	installers["kustomize"] = CliInstaller{Setup: setupKustomize, VersionArgs: []string{"version"}}
+	installers["ibmcloud"] = CliInstaller{Setup: setupIBMCloud, VersionArgs: []string{"version"}}
+	installers["ibmcloud-is"] = CliInstaller{Setup: setupIBMCloudISPlugin, Present: ibmcloudPluginPresent("infrastructure-service"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-ob"] = CliInstaller{Setup: setupIBMCloudOBPlugin, Present: ibmcloudPluginPresent("observe-service"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-ks"] = CliInstaller{Setup: setupIBMCloudKSPlugin, Present: ibmcloudPluginPresent("kubernetes-service"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-cr"] = CliInstaller{Setup: setupIBMCloudCRPlugin, Present: ibmcloudPluginPresent("container-registry"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-ce"] = CliInstaller{Setup: setupIBMCloudCEPlugin, Present: ibmcloudPluginPresent("code-engine"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-sm"] = CliInstaller{Setup: setupIBMCloudSMPlugin, Present: ibmcloudPluginPresent("secrets-manager"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["ibmcloud-cdb"] = CliInstaller{Setup: setupIBMCloudCDBPlugin, Present: ibmcloudPluginPresent("cloud-databases"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["helm-diff"] = CliInstaller{Setup: setupHelmDiffPlugin, Present: helmPluginPresent("diff"), Dependencies: []string{"helm"}, NoLatestRelease: true}
+	installers["ibmcloud-es"] = CliInstaller{Setup: setupIBMCloudESPlugin, Present: ibmcloudPluginPresent("event-streams"), Dependencies: []string{"ibmcloud"}, NoLatestRelease: true}
+	installers["gitu"] = CliInstaller{Setup: setupGitu}
+	installers["gh"] = CliInstaller{Setup: setupGh}
+	installers["glab"] = CliInstaller{Setup: setupGlab}
+	installers["openshift-install"] = CliInstaller{Setup: setupOpenShiftInstall, VersionArgs: []string{"version"}}
+	installers["operator-sdk"] = CliInstaller{Setup: setupOperatorSdk, VersionArgs: []string{"version"}}
+	installers["conftest"] = CliInstaller{Setup: setupConftest}
+	installers["kyverno"] = CliInstaller{Setup: setupKyverno, VersionArgs: []string{"version"}}
+	installers["clusterctl"] = CliInstaller{Setup: setupClusterctl, VersionArgs: []string{"version"}}
+	installers["odo"] = CliInstaller{Setup: setupOdo, VersionArgs: []string{"version"}}
+	installers["checkov"] = CliInstaller{Setup: setupCheckov}
+	installers["infracost"] = CliInstaller{Setup: setupInfracost}
+	installers["syft"] = CliInstaller{Setup: setupSyft, VersionArgs: []string{"version"}}
+	installers["dive"] = CliInstaller{Setup: setupDive}
+	installers["step"] = CliInstaller{Setup: setupStep, VersionArgs: []string{"version"}, FreeBSD: true}
+	installers["age"] = CliInstaller{Setup: setupAge, Present: agePresent, FreeBSD: true}
+	installers["popeye"] = CliInstaller{Setup: setupPopeye, VersionArgs: []string{"version"}}
+	installers["kubescape"] = CliInstaller{Setup: setupKubescape, VersionArgs: []string{"version"}}
+	installers["regctl"] = CliInstaller{Setup: setupRegctl, VersionArgs: []string{"version"}}
+	installers["terragrunt"] = CliInstaller{Setup: setupTerragrunt}
+	installers["terraform-docs"] = CliInstaller{Setup: setupTerraformDocs, FreeBSD: true}
+	installers["packer"] = CliInstaller{Setup: setupPacker, VersionArgs: []string{"version"}, FreeBSD: true}
+	installers["nomad"] = CliInstaller{Setup: setupNomad, VersionArgs: []string{"version"}}
+	installers["buildah"] = CliInstaller{Setup: setupBuildah}
+	installers["hadolint"] = CliInstaller{Setup: setupHadolint}
+	installers["yamllint"] = CliInstaller{Setup: setupYamllint}
+	installers["dagger"] = CliInstaller{Setup: setupDagger, VersionArgs: []string{"version"}}
+	installers["earthly"] = CliInstaller{Setup: setupEarthly}
+	installers["ko"] = CliInstaller{Setup: setupKo, VersionArgs: []string{"version"}}
+	installers["pluto"] = CliInstaller{Setup: setupPluto, VersionArgs: []string{"version"}}
+	installers["etcdctl"] = CliInstaller{Setup: setupEtcdctl, VersionArgs: []string{"version"}}
+	installers["cfssl"] = CliInstaller{Setup: setupCfssl, Present: cfsslPresent, VersionArgs: []string{"version"}}
+	installers["mkcert"] = CliInstaller{Setup: setupMkcert}
+	installers["argo"] = CliInstaller{Setup: setupArgo, VersionArgs: []string{"version"}}
+	installers["argo-rollouts"] = CliInstaller{Setup: setupArgoRollouts, VersionArgs: []string{"version"}, Binary: "kubectl-argo-rollouts"}
+	installers["calicoctl"] = CliInstaller{Setup: setupCalicoctl, VersionArgs: []string{"version", "--client"}}
+	installers["virtctl"] = CliInstaller{Setup: setupVirtctl, VersionArgs: []string{"version", "--client"}}
+	installers["minikube"] = CliInstaller{Setup: setupMinikube, VersionArgs: []string{"version"}}
+	installers["datree"] = CliInstaller{Setup: setupDatree, VersionArgs: []string{"version"}}
+	installers["s2i"] = CliInstaller{Setup: setupS2i, VersionArgs: []string{"version"}}
+	installers["terraformer"] = CliInstaller{Setup: setupTerraformer, VersionArgs: []string{"version"}}
 
 	return installers
 }
 
+// supportedCliNames returns the sorted names of the clis that have an installer
+func supportedCliNames() []string {
+	return getInstallerRegistry().Names()
+}
+
 func getDefaultVersions() map[string]string {
 	if defaultVersions != nil {
 		return defaultVersions
@@ -91,15 +251,19 @@ func getDefaultVersions() map[string]string {
 
 	defaultVersions = make(map[string]string)
 
-	installersMap := getInstallers()
 	// initialize with empty string
-	for k := range installersMap {
+	for _, k := range supportedCliNames() {
 		defaultVersions[k] = ""
 	}
 
 	defaultVersions["jq"] = "1.6"
 	defaultVersions["igc"] = "1.50.2"
 	defaultVersions["gitu"] = "1.15.0"
+	defaultVersions["helm"] = "3.8.2"
+	// kustomize only resolves its latest release (from the kustomize/ prefixed tags) when this default is removed
+	defaultVersions["kustomize"] = "5.4.3"
+	defaultVersions["packer"] = "1.11.2"
+	defaultVersions["nomad"] = "1.8.4"
 
 	return defaultVersions
 }
@@ -111,90 +275,322 @@ func dataClisCheckRead(ctx context.Context, d *schema.ResourceData, m interface{
 	clis := interfacesToString(d.Get("clis").([]interface{}))
 	config := m.(*ProviderConfig)
 
+	ctx = withLinkMode(ctx, config.LinkMode)
+	ctx = withRegistryToken(ctx, config.RegistryToken)
+	if config.Downloader != nil {
+		ctx = withDownloader(ctx, config.Downloader)
+	}
+
 	binDir := config.BinDir
 	envContext := config.EnvContext
 
-	defaultClis := []string{"yq", "jq", "igc", "kubeseal", "oc"}
+	ctx = withCrossInstall(ctx, envContext.isCrossInstall())
+	ctx = withKubectlReleaseCache(ctx)
+
+	if d.Get("install_defaults").(bool) {
+		clis = append(append([]string{}, config.DefaultClis...), clis...)
+	}
+
+	clis = unique(clis)
+
+	if toolVersionsFile := d.Get("tool_versions_file").(string); len(toolVersionsFile) > 0 {
+		toolVersions, err := readToolVersionsFile(toolVersionsFile)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 
-	clis = unique(append(defaultClis, clis...))
+		clis = applyToolVersions(clis, toolVersions)
+	}
 
-	err := addBinDirToPath(binDir)
+	clis, err := orderClisByDependencies(clis)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	for _, cliName := range clis {
-		if _, err := setupNamedCli(cliName, ctx, binDir, envContext); err != nil {
+	if d.Get("no_path_modification").(bool) {
+		ctx = withPathModification(ctx, false)
+	} else {
+		restorePath, err := addBinDirToPath(binDir)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		defer restorePath()
+	}
+
+	installed := make(map[string]interface{})
+	versions := make(map[string]interface{})
+	if !d.Get("install").(bool) {
+		// checking must work on runners where bin_dir is read-only, so nothing is created or linked
+		ctx = withCheckOnly(ctx, true)
+
+		for _, cliName := range clis {
+			present, err := checkNamedCli(cliName, ctx, binDir)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			if name, _, err := parseCliName(cliName); err == nil {
+				installed[name] = false
+			}
+
+			if present {
+				if entry := newManifestEntry(ctx, binDir, cliName, false); len(entry.Version) > 0 {
+					versions[entry.Name] = entry.Version
+				}
+			} else {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("CLI not available: %s", cliName),
+					Detail:   fmt.Sprintf("The cli %s is missing or below the required version and install is disabled", cliName),
+				})
+			}
+		}
+
+		if diags.HasError() {
+			return diags
+		}
+	} else {
+		if err := checkBinDirWritable(binDir); err != nil {
+			return diag.Diagnostics{
+				diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Unable to write to bin_dir: %s", binDir),
+					Detail:   fmt.Sprintf("The clis cannot be installed because %s could not be created or is not writable. Check the path and its permissions: %s", binDir, err.Error()),
+				},
+			}
+		}
+
+		lockFile, err := loadLockFile(binDir)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		// when everything is already present there is nothing to resolve or download
+		satisfied := allClisPresent(ctx, clis, binDir)
+		if satisfied {
+			tflog.Debug(ctx, fmt.Sprintf("All requested clis are already present: %s", strings.Join(clis, ", ")))
+		}
+
+		lockCtx := withLockFile(ctx, lockFile)
+		manifest := Manifest{BinDir: binDir}
+		for _, cliName := range clis {
+			latestRelease := installsLatestRelease(lockFile, cliName)
+
+			cliInstalled := false
+			if !satisfied {
+				cliInstalled, err = setupNamedCli(cliName, lockCtx, binDir, envContext)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+			}
+
+			entry := newManifestEntry(lockCtx, binDir, cliName, cliInstalled)
+			manifest.Clis = append(manifest.Clis, entry)
+			installed[entry.Name] = entry.Installed
+			if len(entry.Version) > 0 {
+				versions[entry.Name] = entry.Version
+			}
+
+			if cliInstalled && latestRelease {
+				diags = append(diags, latestVersionWarning(entry))
+			}
+		}
+
+		if err := lockFile.save(); err != nil {
 			return diag.FromErr(err)
 		}
+
+		if manifestPath := d.Get("manifest_path").(string); len(manifestPath) > 0 {
+			if err := writeManifest(manifestPath, manifest); err != nil {
+				return diag.FromErr(err)
+			}
+		}
 	}
 
 	if err = d.Set("bin_dir", binDir); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId("clis:" + strings.Join(clis[:], ":"))
+	if err = d.Set("ibmcloud_home", getIBMCloudHome(binDir)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("installed", installed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("versions", versions); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("clis:" + strings.Join(resolvedCliIds(clis, versions), ":"))
 
 	return diags
 }
 
-func addBinDirToPath(binDir string) error {
-	if len(binDir) == 0 {
-		return nil
+// lookPathInBinDir finds the cli in the PATH. When bin_dir has not been added to the PATH it is checked first
+func lookPathInBinDir(ctx context.Context, destDir string, cliName string) (string, error) {
+	if !pathModificationFromContext(ctx) {
+		binDirPath := filepath.Join(destDir, cliName)
+		if info, err := os.Stat(binDirPath); err == nil && !info.IsDir() {
+			return binDirPath, nil
+		}
 	}
 
-	if !strings.HasPrefix(binDir, "/") {
-		cwd, err := os.Getwd()
-		if err != nil {
-			cwd = "."
+	return exec.LookPath(cliName)
+}
+
+// parseCliName splits a versioned cli string (e.g. helm@3.8.2 or helm-3.8.2) into the cli name and version,
+// falling back to the default version for the cli. The name@version form is unambiguous for hyphenated names
+func parseCliName(cliName string) (string, string, error) {
+	cliVersion := ""
+	if strings.Contains(cliName, "@") {
+		nameParts := strings.SplitN(cliName, "@", 2)
+
+		if len(nameParts[0]) == 0 || len(nameParts[1]) == 0 {
+			return "", "", fmt.Errorf("unable to parse versioned cli string: %s", cliName)
+		}
+
+		cliName = nameParts[0]
+		cliVersion = strings.TrimPrefix(nameParts[1], "v")
+	} else if versionedInstallRe.MatchString(cliName) {
+		nameParts := versionedInstallRe.FindStringSubmatch(cliName)
+
+		if len(nameParts) < 3 {
+			return "", "", fmt.Errorf("unable to parse versioned cli string: %s", cliName)
 		}
 
-		binDir = path.Join(cwd, binDir)
+		cliName = nameParts[1]
+		cliVersion = nameParts[2]
+	}
+
+	if len(cliVersion) > 0 {
+		if _, err := version.NewVersion(cliVersion); err != nil {
+			return "", "", fmt.Errorf("invalid version for cli: %s, %s", cliName, err.Error())
+		}
 	}
 
-	cliPath := os.Getenv("PATH")
-	err := os.Setenv("PATH", fmt.Sprintf("%s:%s", binDir, cliPath))
+	if len(cliVersion) == 0 {
+		cliVersion = getDefaultVersions()[cliName]
+	}
 
-	return err
+	return cliName, cliVersion, nil
 }
 
 func setupNamedCli(cliName string, ctx context.Context, destDir string, envContext EnvContext) (bool, error) {
-	if cliName == "kubectl" {
-		return false, nil
+	cliName, version, err := parseCliName(cliName)
+	if err != nil {
+		return false, err
 	}
 
-	installers := getInstallers()
+	cliMutexKV.Lock(cliName)
+	defer cliMutexKV.Unlock(cliName)
 
-	version := ""
-	if versionedInstallRe.MatchString(cliName) {
-		nameParts := versionedInstallRe.FindStringSubmatch(cliName)
+	err = os.MkdirAll(destDir, os.ModePerm)
+	if err != nil {
+		return false, err
+	}
 
-		if len(nameParts) < 3 {
-			return false, fmt.Errorf("unable to parse versioned cli string: %s", cliName)
+	// the latest release is only resolved again if the lock file has no version for the cli
+	if len(version) == 0 {
+		if lockedVersion, ok := lockFileFromContext(ctx).lockedVersion(getCliBinary(cliName), version); ok {
+			tflog.Debug(ctx, fmt.Sprintf("Using version from lock file for cli: %s, %s", cliName, lockedVersion))
+
+			ctx = withRequestedVersion(ctx, version)
+			version = lockedVersion
 		}
+	}
 
-		cliName = nameParts[1]
-		version = nameParts[2]
+	installer, ok := getInstallerRegistry().Get(cliName)
+	if !ok {
+		return false, fmt.Errorf("unable to find installer for cli: %s", cliName)
 	}
 
-	if len(version) == 0 {
-		version = getDefaultVersions()[cliName]
+	return installer.Install(ctx, destDir, envContext, version)
+}
+
+// resolvedCliIds appends the resolved version to each of the requested clis (e.g. helm=3.8.2 or helm@3.8=3.8.2)
+func resolvedCliIds(clis []string, versions map[string]interface{}) []string {
+	result := make([]string, len(clis))
+	for i, cliName := range clis {
+		result[i] = cliName
+
+		name, _, err := parseCliName(cliName)
+		if err != nil {
+			continue
+		}
+
+		if version, ok := versions[name]; ok {
+			result[i] = fmt.Sprintf("%s=%s", cliName, version)
+		}
 	}
 
-	cliMutexKV.Lock(cliName)
-	defer cliMutexKV.Unlock(cliName)
+	return result
+}
+
+// installsLatestRelease reports whether setupNamedCli installs the latest release of the cli, i.e. no version is
+// requested and the lock file has no version for the cli. The lock file must be checked before the install records
+// the version it resolves
+func installsLatestRelease(lockFile *LockFile, cliName string) bool {
+	name, version, err := parseCliName(cliName)
+	if err != nil || len(version) > 0 || getInstallers()[name].NoLatestRelease {
+		return false
+	}
+
+	_, locked := lockFile.lockedVersion(getCliBinary(name), version)
+
+	return !locked
+}
+
+// latestVersionWarning nudges the user to pin a cli that was installed from whatever release was current
+func latestVersionWarning(entry ManifestEntry) diag.Diagnostic {
+	resolvedVersion := entry.Version
+	pinnedName := entry.Name + "@" + entry.Version
+	if len(resolvedVersion) == 0 {
+		resolvedVersion = "unknown version"
+		pinnedName = entry.Name + "@<version>"
+	}
+
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Installed the latest release of cli: %s (%s)", entry.Name, resolvedVersion),
+		Detail:   fmt.Sprintf("No version was requested for %s so the install is not reproducible and may change the next time it runs. Request a version (e.g. %s) to pin it.", entry.Name, pinnedName),
+	}
+}
+
+// allClisPresent reports whether every cli is already available at the required version. Any error is left to
+// the install to report
+func allClisPresent(ctx context.Context, clis []string, destDir string) bool {
+	for _, cliName := range clis {
+		present, err := checkNamedCli(cliName, ctx, destDir)
+		if err != nil || !present {
+			return false
+		}
+	}
+
+	return true
+}
 
-	err := os.MkdirAll(destDir, os.ModePerm)
+// checkNamedCli reports whether the cli is already available at the required version without installing it
+func checkNamedCli(cliName string, ctx context.Context, destDir string) (bool, error) {
+	cliName, version, err := parseCliName(cliName)
 	if err != nil {
 		return false, err
 	}
 
-	setupCli := installers[cliName]
-	if setupCli == nil {
+	cliMutexKV.Lock(cliName)
+	defer cliMutexKV.Unlock(cliName)
+
+	installer, ok := getInstallerRegistry().Get(cliName)
+	if !ok {
 		return false, fmt.Errorf("unable to find installer for cli: %s", cliName)
 	}
 
-	return setupCli(ctx, destDir, envContext, version)
+	if checker, ok := installer.(PresenceChecker); ok {
+		return checker.Present(ctx, destDir, version), nil
+	}
+
+	return cliAlreadyPresent(ctx, destDir, cliName, version), nil
 }
 
 func setupJq(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
@@ -226,7 +622,7 @@ func setupIgc(ctx context.Context, destDir string, envContext EnvContext, versio
 	gitOrg := "cloud-native-toolkit"
 	gitRepo := "ibm-garage-cloud-cli"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
@@ -241,7 +637,7 @@ func setupIgc(ctx context.Context, destDir string, envContext EnvContext, versio
 	var osName string
 	if envContext.isMacOs() {
 		osName = "macos"
-	} else if envContext.isAlpine() {
+	} else if envContext.isMusl() {
 		osName = "alpine"
 	} else {
 		osName = "linux"
@@ -266,18 +662,39 @@ func setupYq(ctx context.Context, destDir string, envContext EnvContext, _ strin
 	return yq3Result || yq4Result, nil
 }
 
+// yqPresent checks for the yq3 and yq4 clis that setupYq provides. A yq in the PATH doesn't count because setupYq
+// still has to link it into bin_dir under the versioned name
+func yqPresent(ctx context.Context, destDir string, _ string) bool {
+	if crossInstallFromContext(ctx) {
+		// the yq clis for another platform can't be run so only the files in bin_dir are checked
+		yq3Present, _ := fileExists(filepath.Join(destDir, "yq3"))
+		yq4Present, _ := fileExists(filepath.Join(destDir, "yq4"))
+
+		return yq3Present && yq4Present
+	}
+
+	yq3Present := checkCurrentVersion(ctx, destDir, "yq3", []string{"--version"}, "^3[.][0-9]*")
+	yq4Present := checkCurrentVersion(ctx, destDir, "yq4", []string{"--version"}, "^4[.][0-9]*")
+
+	return yq3Present && yq4Present
+}
+
 func setupYq3(ctx context.Context, destDir string, envContext EnvContext, _ string) (bool, error) {
 	cliName := "yq3"
-	if checkCurrentVersion(ctx, "yq", []string{"--version"}, "^3[.][0-9]*") {
-		return createSymLink("yq", path.Join(destDir, cliName))
-	}
-	if checkCurrentVersion(ctx, "yq3", []string{"--version"}, "^3[.][0-9]*") {
-		return createSymLink("yq3", path.Join(destDir, cliName))
+	if !crossInstallFromContext(ctx) {
+		if checkCurrentVersion(ctx, destDir, "yq", []string{"--version"}, "^3[.][0-9]*") {
+			return createLink(ctx, "yq", path.Join(destDir, cliName))
+		}
+		if checkCurrentVersion(ctx, destDir, "yq3", []string{"--version"}, "^3[.][0-9]*") {
+			return createLink(ctx, "yq3", path.Join(destDir, cliName))
+		}
 	}
 
 	var osName string
 	if envContext.isMacOs() {
 		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
 	} else {
 		osName = "linux"
 	}
@@ -296,16 +713,20 @@ func setupYq3(ctx context.Context, destDir string, envContext EnvContext, _ stri
 
 func setupYq4(ctx context.Context, destDir string, envContext EnvContext, _ string) (bool, error) {
 	cliName := "yq4"
-	if checkCurrentVersion(ctx, "yq", []string{"--version"}, "^4[.][0-9]*") {
-		return createSymLink("yq", path.Join(destDir, cliName))
-	}
-	if checkCurrentVersion(ctx, "yq4", []string{"--version"}, "^4[.][0-9]*") {
-		return createSymLink("yq4", path.Join(destDir, cliName))
+	if !crossInstallFromContext(ctx) {
+		if checkCurrentVersion(ctx, destDir, "yq", []string{"--version"}, "^4[.][0-9]*") {
+			return createLink(ctx, "yq", path.Join(destDir, cliName))
+		}
+		if checkCurrentVersion(ctx, destDir, "yq4", []string{"--version"}, "^4[.][0-9]*") {
+			return createLink(ctx, "yq4", path.Join(destDir, cliName))
+		}
 	}
 
 	var osName string
 	if envContext.isMacOs() {
 		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
 	} else {
 		osName = "linux"
 	}
@@ -342,12 +763,17 @@ func setupHelm(ctx context.Context, destDir string, envContext EnvContext, minVe
 		arch = "amd64"
 	}
 
-	filename := fmt.Sprintf("helm-v3.8.2-%s-%s.tar.gz", osName, arch)
+	filename := fmt.Sprintf("helm-v%s-%s-%s.tar.gz", strings.TrimPrefix(minVersion, "v"), osName, arch)
 	tgzPath := fmt.Sprintf("%s-%s/helm", osName, arch)
 
 	url := fmt.Sprintf("https://get.helm.sh/%s", filename)
 
-	return setupBinaryFromTgz(ctx, destDir, cliName, url, tgzPath, []string{"version"}, minVersion)
+	checksum, err := getPublishedChecksum(ctx, url+".sha256sum", filename)
+	if err != nil {
+		return false, err
+	}
+
+	return setupBinaryFromTgzWithChecksum(ctx, destDir, cliName, url, tgzPath, []string{"version"}, minVersion, checksum)
 }
 
 func setupArgoCD(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
@@ -359,7 +785,13 @@ func setupArgoCD(ctx context.Context, destDir string, envContext EnvContext, min
 	gitOrg := "argoproj"
 	gitRepo := "argo-cd"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	var releaseInfo *GitHubRelease
+	var err error
+	if len(minVersion) > 0 {
+		releaseInfo, err = getGitHubRelease(ctx, gitOrg, gitRepo, minVersion)
+	} else {
+		releaseInfo, err = getLatestStableGitHubRelease(ctx, gitOrg, gitRepo)
+	}
 	if err != nil {
 		return false, err
 	}
@@ -403,7 +835,12 @@ func setupRosa(ctx context.Context, destDir string, envContext EnvContext, minVe
 		arch = "amd64"
 	}
 
-	url := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/%s/clients/rosa/latest/rosa-%s.tar.gz", arch, osName)
+	release := "latest"
+	if len(minVersion) > 0 {
+		release = strings.TrimPrefix(minVersion, "v")
+	}
+
+	url := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/%s/clients/rosa/%s/rosa-%s.tar.gz", arch, release, osName)
 
 	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
 }
@@ -417,7 +854,7 @@ func setupKubeseal(ctx context.Context, destDir string, envContext EnvContext, m
 	gitOrg := "bitnami-labs"
 	gitRepo := "sealed-secrets"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getGitHubRelease(ctx, gitOrg, gitRepo, minVersion)
 	if err != nil {
 		return false, err
 	}
@@ -443,23 +880,10 @@ func setupKubeseal(ctx context.Context, destDir string, envContext EnvContext, m
 	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"--version"}, minVersion)
 }
 
-func setupKube(ctx context.Context, destDir string, envContext EnvContext, _ string) (bool, error) {
-	ocResult, err := setupOc(ctx, destDir, envContext)
-	if err != nil {
-		return false, err
-	}
-
-	kubectlResult, err := setupKubectl(ctx, destDir, envContext)
-	if err != nil {
-		return false, err
-	}
-
-	return ocResult || kubectlResult, nil
-}
-
-func setupOc(ctx context.Context, destDir string, envContext EnvContext) (bool, error) {
+// setupOc installs the requested version of oc or, if no version is provided, the current stable release
+func setupOc(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
 	cliName := "oc"
-	if cliAlreadyPresent(ctx, destDir, cliName, "") {
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
 		return false, nil
 	}
 
@@ -477,14 +901,20 @@ func setupOc(ctx context.Context, destDir string, envContext EnvContext) (bool,
 		arch = "amd64"
 	}
 
-	url := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/%s/clients/ocp/stable/openshift-client-%s.tar.gz", arch, osName)
+	channel := "stable"
+	if len(version) > 0 {
+		channel = strings.TrimPrefix(version, "v")
+	}
+
+	url := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/%s/clients/ocp/%s/openshift-client-%s.tar.gz", arch, channel, osName)
 
-	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version", "--client"}, "")
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version", "--client"}, version)
 }
 
-func setupKubectl(ctx context.Context, destDir string, envContext EnvContext) (bool, error) {
+// setupKubectl installs the requested version of kubectl or, if no version is provided, the current stable release
+func setupKubectl(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
 	cliName := "kubectl"
-	if cliAlreadyPresent(ctx, destDir, cliName, "") {
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
 		return false, nil
 	}
 
@@ -502,9 +932,65 @@ func setupKubectl(ctx context.Context, destDir string, envContext EnvContext) (b
 		arch = "amd64"
 	}
 
-	resp, err := http.Get("https://dl.k8s.io/release/stable.txt")
+	var release string
+	if len(version) > 0 {
+		// a pinned version doesn't need the stable release lookup
+		release = "v" + strings.TrimPrefix(version, "v")
+	} else {
+		stableRelease, err := getStableKubectlRelease(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		release = stableRelease
+	}
+
+	url := fmt.Sprintf("https://dl.k8s.io/release/%s/bin/%s/%s/kubectl", release, osName, arch)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version", "--client"}, version)
+}
+
+type kubectlReleaseCacheContextKey struct{}
+
+// kubectlReleaseCache holds the stable kubectl release so that it is only looked up once per read
+type kubectlReleaseCache struct {
+	once    sync.Once
+	release string
+	err     error
+}
+
+func withKubectlReleaseCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, kubectlReleaseCacheContextKey{}, &kubectlReleaseCache{})
+}
+
+func kubectlReleaseCacheFromContext(ctx context.Context) *kubectlReleaseCache {
+	cache, _ := ctx.Value(kubectlReleaseCacheContextKey{}).(*kubectlReleaseCache)
+
+	return cache
+}
+
+// getStableKubectlRelease returns the current stable kubectl release (e.g. v1.29.4), using the value cached in the
+// context if it has already been looked up
+func getStableKubectlRelease(ctx context.Context) (string, error) {
+	cache := kubectlReleaseCacheFromContext(ctx)
+	if cache == nil {
+		return fetchStableKubectlRelease(ctx)
+	}
+
+	cache.once.Do(func() {
+		cache.release, cache.err = fetchStableKubectlRelease(ctx)
+	})
+
+	return cache.release, cache.err
+}
+
+func fetchStableKubectlRelease(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, kubectlStableTimeout)
+	defer cancel()
+
+	resp, err := httpGetWithRetry(ctx, kubectlStableUrl)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("unable to retrieve kubectl release from %s: %w", kubectlStableUrl, err)
 	}
 	defer func() {
 		if tmpError := resp.Body.Close(); tmpError != nil {
@@ -512,16 +998,27 @@ func setupKubectl(ctx context.Context, destDir string, envContext EnvContext) (b
 		}
 	}()
 
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving kubectl release from %s: %s", kubectlStableUrl, resp.Status)
+	}
+
 	buf := new(strings.Builder)
 	_, err = io.Copy(buf, resp.Body)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("unable to read kubectl release from %s: %w", kubectlStableUrl, err)
 	}
-	release := buf.String()
 
-	url := fmt.Sprintf("https://dl.k8s.io/release/%s/bin/%s/%s/kubectl", release, osName, arch)
+	release := strings.TrimSpace(buf.String())
+	if !kubectlReleaseRe.MatchString(release) {
+		// the body may be an error page so only part of it is included
+		if len(release) > 64 {
+			release = release[:64] + "..."
+		}
 
-	return setupBinary(ctx, destDir, cliName, url, []string{"version", "--client"}, "")
+		return "", fmt.Errorf("unexpected kubectl release returned from %s: %q", kubectlStableUrl, release)
+	}
+
+	return release, err
 }
 
 func setupKustomize(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
@@ -544,13 +1041,34 @@ func setupKustomize(ctx context.Context, destDir string, envContext EnvContext,
 		arch = "amd64"
 	}
 
-	filename := fmt.Sprintf("kustomize_v4.5.4_%s_%s.tar.gz", osName, arch)
+	// a version is always requested while kustomize has a default version, so the latest release is only looked
+	// up if the default is removed
+	version := minVersion
+	if len(version) == 0 {
+		releaseInfo, err := getLatestGitHubRelease(ctx, "kubernetes-sigs", "kustomize", "kustomize/")
+		if err != nil {
+			return false, err
+		}
+
+		version = strings.TrimPrefix(releaseInfo.TagName, "kustomize/")
+	}
 
-	url := "https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize%2Fv4.5.4/" + filename
+	url := getKustomizeUrl(version, osName, arch)
 
 	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
 }
 
+// getKustomizeUrl builds the release url for the kustomize version. The release tags are prefixed
+// with "kustomize/", which must be url encoded in the download path
+func getKustomizeUrl(version string, osName string, arch string) string {
+	shortVersion := strings.TrimPrefix(version, "v")
+
+	filename := fmt.Sprintf("kustomize_v%s_%s_%s.tar.gz", shortVersion, osName, arch)
+	tag := strings.Replace(fmt.Sprintf("kustomize/v%s", shortVersion), "/", "%2F", -1)
+
+	return fmt.Sprintf("https://github.com/kubernetes-sigs/kustomize/releases/download/%s/%s", tag, filename)
+}
+
 func setupGitu(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
 	cliName := "gitu"
 	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
@@ -560,7 +1078,7 @@ func setupGitu(ctx context.Context, destDir string, envContext EnvContext, minVe
 	gitOrg := "cloud-native-toolkit"
 	gitRepo := "git-client"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
@@ -568,7 +1086,7 @@ func setupGitu(ctx context.Context, destDir string, envContext EnvContext, minVe
 	var osName string
 	if envContext.isMacOs() {
 		osName = "macos"
-	} else if envContext.isAlpine() {
+	} else if envContext.isMusl() {
 		osName = "alpine"
 	} else {
 		osName = "linux"
@@ -595,7 +1113,7 @@ func setupGh(ctx context.Context, destDir string, envContext EnvContext, minVers
 	gitOrg := "cli"
 	gitRepo := "cli"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
@@ -621,7 +1139,12 @@ func setupGh(ctx context.Context, destDir string, envContext EnvContext, minVers
 	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, filename)
 	tgzPath := fmt.Sprintf("%s/bin/gh", filename)
 
-	return setupBinaryFromTgz(ctx, destDir, cliName, url, tgzPath, []string{"--version"}, minVersion)
+	checksum, err := getGitHubReleaseChecksum(ctx, gitOrg, gitRepo, releaseInfo.TagName, fmt.Sprintf("gh_%s_checksums.txt", shortRelease), filename+".tar.gz")
+	if err != nil {
+		return false, err
+	}
+
+	return setupBinaryFromTgzWithChecksum(ctx, destDir, cliName, url, tgzPath, []string{"--version"}, minVersion, checksum)
 }
 
 func setupGlab(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
@@ -630,37 +1153,54 @@ func setupGlab(ctx context.Context, destDir string, envContext EnvContext, minVe
 		return false, nil
 	}
 
-	gitOrg := "profclems"
-	gitRepo := "glab"
-
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	// glab moved from github.com/profclems/glab to gitlab.com/gitlab-org/cli, where the releases are now published
+	releaseInfo, err := getLatestGitLabRelease(ctx, defaultGitLabHost, "gitlab-org/cli")
 	if err != nil {
 		return false, err
 	}
 
 	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
 
-	var osName string
+	// older releases capitalize the os name and use x86_64 for amd64
+	var osName, legacyOsName string
 	if envContext.isMacOs() {
-		osName = "macOS"
+		osName = "darwin"
+		legacyOsName = "macOS"
 	} else {
-		osName = "Linux"
+		osName = "linux"
+		legacyOsName = "Linux"
 	}
 
-	var arch string
+	var arch, legacyArch string
 	if envContext.isArmArch() {
 		arch = "arm64"
+		legacyArch = "arm64"
 	} else {
-		arch = "x86_64"
+		arch = "amd64"
+		legacyArch = "x86_64"
 	}
 
-	filename := fmt.Sprintf("glab_%s_%s_%s", shortRelease, osName, arch)
+	assetName, url, err := getGitLabReleaseAssetUrl(
+		releaseInfo,
+		fmt.Sprintf("glab_%s_%s_%s.tar.gz", shortRelease, osName, arch),
+		fmt.Sprintf("glab_%s_%s_%s.tar.gz", shortRelease, legacyOsName, legacyArch),
+	)
+	if err != nil {
+		return false, err
+	}
 
-	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, filename)
-	tgzPath := "bin/glab"
+	_, checksumUrl, err := getGitLabReleaseAssetUrl(releaseInfo, "checksums.txt")
+	if err != nil {
+		return false, err
+	}
 
-	return setupBinaryFromTgz(ctx, destDir, cliName, url, tgzPath, []string{"--version"}, minVersion)
-}
+	checksum, err := getPublishedChecksum(ctx, checksumUrl, assetName)
+	if err != nil {
+		return false, err
+	}
+
+	return setupBinaryFromTgzWithChecksum(ctx, destDir, cliName, url, "bin/glab", []string{"--version"}, minVersion, checksum)
+}
 
 func setupOpenShiftInstall(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
 	cliName := "openshift-install"
@@ -703,7 +1243,7 @@ func setupIBMCloud(ctx context.Context, destDir string, envContext EnvContext, _
 	gitOrg := "IBM-Cloud"
 	gitRepo := "ibm-cloud-cli-release"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
@@ -732,7 +1272,13 @@ func setupIBMCloud(ctx context.Context, destDir string, envContext EnvContext, _
 		return false, err
 	}
 
-	cmd := exec.Command(filepath.Join(destDir, cliName), []string{"config", "--check-version=false"}...)
+	if crossInstallFromContext(ctx) {
+		// the ibmcloud cli for another platform can't be run to configure it
+		tflog.Debug(ctx, "Skipping ibmcloud config for cross-platform install")
+		return result, nil
+	}
+
+	cmd := ibmcloudCommand(destDir, []string{"config", "--check-version=false"}...)
 	err = cmd.Run()
 	if err != nil {
 		return false, err
@@ -741,32 +1287,63 @@ func setupIBMCloud(ctx context.Context, destDir string, envContext EnvContext, _
 	return result, err
 }
 
-func setupIBMCloudISPlugin(ctx context.Context, destDir string, _ EnvContext, _ string) (bool, error) {
-	return setupIBMCloudPlugin(ctx, destDir, "infrastructure-service")
+func setupIBMCloudISPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "infrastructure-service", version)
+}
+
+func setupIBMCloudCRPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "container-registry", version)
+}
+
+func setupIBMCloudKSPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "kubernetes-service", version)
+}
+
+func setupIBMCloudOBPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "observe-service", version)
+}
+
+func setupIBMCloudCEPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "code-engine", version)
+}
+
+func setupIBMCloudSMPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "secrets-manager", version)
 }
 
-func setupIBMCloudCRPlugin(ctx context.Context, destDir string, _ EnvContext, _ string) (bool, error) {
-	return setupIBMCloudPlugin(ctx, destDir, "container-registry")
+func setupIBMCloudCDBPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "cloud-databases", version)
 }
 
-func setupIBMCloudKSPlugin(ctx context.Context, destDir string, _ EnvContext, _ string) (bool, error) {
-	return setupIBMCloudPlugin(ctx, destDir, "kubernetes-service")
+func setupIBMCloudESPlugin(ctx context.Context, destDir string, _ EnvContext, version string) (bool, error) {
+	return setupIBMCloudPlugin(ctx, destDir, "event-streams", version)
 }
 
-func setupIBMCloudOBPlugin(ctx context.Context, destDir string, _ EnvContext, _ string) (bool, error) {
-	return setupIBMCloudPlugin(ctx, destDir, "observe-service")
+func ibmcloudPluginPresent(pluginName string) func(ctx context.Context, destDir string, version string) bool {
+	return func(ctx context.Context, destDir string, version string) bool {
+		return ibmcloudPluginExists(ctx, destDir, pluginName, version)
+	}
 }
 
-func setupIBMCloudPlugin(ctx context.Context, destDir string, pluginName string) (bool, error) {
+func setupIBMCloudPlugin(ctx context.Context, destDir string, pluginName string, version string) (bool, error) {
+	if crossInstallFromContext(ctx) {
+		return false, crossInstallPluginError(pluginName)
+	}
 
-	if ibmcloudPluginExists(ctx, destDir, pluginName) {
+	if ibmcloudPluginExists(ctx, destDir, pluginName, version) {
 		tflog.Debug(ctx, fmt.Sprintf("Plugin already installed: %s", pluginName))
 		return false, nil
 	}
 
-	tflog.Info(ctx, fmt.Sprintf("Installing plugin: %s", pluginName))
+	tflog.Info(ctx, fmt.Sprintf("Installing plugin: %s %s", pluginName, version))
+
+	args := []string{"plugin", "install", pluginName}
+	if len(version) > 0 {
+		// force replaces a different version of the plugin that is already installed
+		args = append(args, "-v", version, "-f")
+	}
 
-	cmd := exec.Command(filepath.Join(destDir, "ibmcloud"), []string{"plugin", "install", pluginName}...)
+	cmd := ibmcloudCommand(destDir, args...)
 	err := cmd.Run()
 	if err != nil {
 		return false, err
@@ -775,6 +1352,90 @@ func setupIBMCloudPlugin(ctx context.Context, destDir string, pluginName string)
 	return true, nil
 }
 
+func setupHelmDiffPlugin(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
+	return setupHelmPlugin(ctx, destDir, envContext, "diff", "https://github.com/databus23/helm-diff", version)
+}
+
+func helmPluginPresent(pluginName string) func(ctx context.Context, destDir string, version string) bool {
+	return func(ctx context.Context, destDir string, version string) bool {
+		return helmPluginExists(ctx, destDir, pluginName, version)
+	}
+}
+
+// setupHelmPlugin installs the plugin from the url with the helm cli in destDir. helm is a dependency of the helm
+// plugins so it has already been installed
+func setupHelmPlugin(ctx context.Context, destDir string, _ EnvContext, pluginName string, url string, version string) (bool, error) {
+	if crossInstallFromContext(ctx) {
+		return false, crossInstallPluginError("helm-" + pluginName)
+	}
+
+	if helmPluginExists(ctx, destDir, pluginName, version) {
+		tflog.Debug(ctx, fmt.Sprintf("Helm plugin already installed: %s", pluginName))
+		return false, nil
+	}
+
+	if len(version) > 0 && helmPluginExists(ctx, destDir, pluginName, "") {
+		// helm can't install over a different version of the plugin so the existing one is removed first
+		if err := helmCommand(destDir, "plugin", "uninstall", pluginName).Run(); err != nil {
+			return false, fmt.Errorf("unable to remove helm plugin %s: %w", pluginName, err)
+		}
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Installing helm plugin: %s %s", pluginName, version))
+
+	args := []string{"plugin", "install", url}
+	if len(version) > 0 {
+		args = append(args, "--version", version)
+	}
+
+	if out, err := helmCommand(destDir, args...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("unable to install helm plugin %s: %w, %s", pluginName, err, strings.TrimSpace(string(out)))
+	}
+
+	return true, nil
+}
+
+// helmPluginExists checks the output of helm plugin list for the plugin and, if provided, the version
+func helmPluginExists(ctx context.Context, destDir string, pluginName string, version string) bool {
+	if crossInstallFromContext(ctx) {
+		return false
+	}
+
+	out, err := helmCommand(destDir, "plugin", "list").Output()
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Unable to list helm plugins: %s", err.Error()))
+		return false
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != pluginName {
+			continue
+		}
+
+		if len(version) == 0 || strings.TrimPrefix(fields[1], "v") == strings.TrimPrefix(version, "v") {
+			return true
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Helm plugin version does not match requested version: %s, %s != %s", pluginName, fields[1], version))
+		return false
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Helm plugin not already installed: %s", pluginName))
+	return false
+}
+
+// crossInstallPluginError is returned by the plugin installers when the clis are installed for another platform
+// because the plugins are installed by running the parent cli
+func crossInstallPluginError(pluginName string) error {
+	return fmt.Errorf("cannot install plugins when target_os/target_arch differ from the provider platform: %s", pluginName)
+}
+
+// helmCommand creates a command for the helm cli in destDir
+func helmCommand(destDir string, args ...string) *exec.Cmd {
+	return exec.Command(filepath.Join(destDir, "helm"), args...)
+}
+
 func setupOperatorSdk(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
 	cliName := "operator-sdk"
 	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
@@ -784,7 +1445,7 @@ func setupOperatorSdk(ctx context.Context, destDir string, envContext EnvContext
 	gitOrg := "operator-framework"
 	gitRepo := "operator-sdk"
 
-	releaseInfo, err := getLatestGitHubRelease(gitOrg, gitRepo)
+	releaseInfo, err := getLatestStableGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
@@ -803,251 +1464,1949 @@ func setupOperatorSdk(ctx context.Context, destDir string, envContext EnvContext
 		arch = "amd64"
 	}
 
-	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/operator-sdk_%s_%s", gitOrg, gitRepo, releaseInfo.TagName, osName, arch)
-
-	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
-}
+	filename := fmt.Sprintf("operator-sdk_%s_%s", osName, arch)
 
-func ibmcloudPluginExists(ctx context.Context, destDir string, pluginName string) bool {
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
 
-	cmd := exec.Command(filepath.Join(destDir, "ibmcloud"), []string{"plugin", "show", pluginName}...)
-	err := cmd.Run()
+	checksum, err := getGitHubReleaseChecksum(ctx, gitOrg, gitRepo, releaseInfo.TagName, "checksums.txt", filename)
 	if err != nil {
-		tflog.Debug(ctx, fmt.Sprintf("IBM Cloud plugin not already installed: %s", pluginName))
-		return false
+		return false, err
 	}
 
-	return true
+	return setupBinaryWithChecksum(ctx, destDir, cliName, url, []string{"version"}, minVersion, checksum)
 }
 
-func getLatestGitHubRelease(org string, repo string) (*GitHubRelease, error) {
-
-	url := fmt.Sprintf("https://github.com/%s/%s/releases/latest", org, repo)
-
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
+func setupConftest(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "conftest"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
 	}
 
-	resp, err := client.Get(url)
+	gitOrg := "open-policy-agent"
+	gitRepo := "conftest"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	defer func() {
-		if tmpError := resp.Body.Close(); tmpError != nil {
-			err = tmpError
-		}
-	}()
 
-	latestUrl := resp.Header.Get("Location")
-	if len(latestUrl) == 0 {
-		return nil, fmt.Errorf("unable to retrieve location header from url: %s", url)
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "Darwin"
+	} else {
+		osName = "Linux"
 	}
 
-	latestTagMatch := regexp.MustCompile(".*/tag/(.+)").FindStringSubmatch(latestUrl)
-	if len(latestTagMatch) < 2 {
-		return nil, fmt.Errorf("unable to parse latest tag from url: %s", latestUrl)
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "x86_64"
 	}
 
-	releaseInfo := &GitHubRelease{}
-	releaseInfo.TagName = latestTagMatch[1]
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/conftest_%s_%s_%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, shortRelease, osName, arch)
 
-	return releaseInfo, err
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"--version"}, minVersion)
 }
 
-func cliAlreadyPresent(ctx context.Context, destDir string, cliName string, minVersion string) bool {
-	cliPath, err := exec.LookPath(cliName)
-	if err != nil || len(cliPath) == 0 {
-		tflog.Debug(ctx, fmt.Sprintf("CLI not found in path: %s (%s)", cliName, err.Error()))
-		return false
-	}
-
-	if strings.HasPrefix(cliPath, destDir) {
-		tflog.Debug(ctx, fmt.Sprintf("CLI already provided in bin_dir: %s", cliName))
-		return true
+func setupKyverno(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
+	cliName := "kyverno"
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
+		return false, nil
 	}
 
-	if len(minVersion) > 0 {
-		out, err := exec.Command(cliName, "--version").Output()
-		if err != nil {
-			tflog.Warn(ctx, fmt.Sprintf("Error getting cli version: %s", cliName))
-		} else {
-			versionString := cleanVersionString(string(out))
-			if len(out) > 0 {
-				tflog.Debug(ctx, fmt.Sprintf("Found version for cli: %s, %s", cliName, versionString))
-
-				currentVersion, err1 := version.NewVersion(versionString)
-				desiredVersion, err2 := version.NewVersion(minVersion)
-
-				if err1 != nil {
-					log.Fatal(err1)
-				} else if err2 != nil {
-					log.Fatal(err2)
-				} else if currentVersion.LessThan(desiredVersion) {
-					tflog.Debug(ctx, fmt.Sprintf("Current cli version is earlier than required version: %s < %s", versionString, minVersion))
-					return false
-				} else if currentVersion.GreaterThanOrEqual(desiredVersion) {
-					tflog.Debug(ctx, fmt.Sprintf("Current cli version is same or newer than required version: %s >= %s", versionString, minVersion))
-				}
-			}
-		}
-	}
+	gitOrg := "kyverno"
+	gitRepo := "kyverno"
 
-	tflog.Debug(ctx, fmt.Sprintf("CLI already available in PATH: %s. Creating symlink in %s", cliPath, destDir))
-	result, err := createSymLink(cliName, filepath.Join(destDir, cliName))
+	releaseInfo, err := getGitHubRelease(ctx, gitOrg, gitRepo, version)
 	if err != nil {
-		tflog.Error(ctx, fmt.Sprintf("Error creating symlink: %s, %s", cliName, err.Error()))
+		return false, err
 	}
 
-	return result
-}
-
-func cleanVersionString(value string) string {
-	regEx := `[^\d]*(?P<Major>\d+).(?P<Minor>\d+)[.]?(?P<Patch>\d*).*`
-	var compRegEx = regexp.MustCompile(regEx)
-	match := compRegEx.FindStringSubmatch(value)
-
-	cleanValue := ""
-	for i, _ := range compRegEx.SubexpNames() {
-		if i > 0 && i <= len(match) {
-			matchValue := match[i]
-
-			if len(matchValue) == 0 {
-				matchValue = "0"
-			}
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
 
-			if i > 1 {
-				cleanValue = cleanValue + "."
-			}
-			cleanValue = cleanValue + matchValue
-		}
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "x86_64"
 	}
 
-	return cleanValue
-}
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/kyverno-cli_%s_%s_%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, releaseInfo.TagName, osName, arch)
 
-func setupBinary(ctx context.Context, destDir string, cliName string, url string, testArgs []string, minVersion string) (bool, error) {
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, version)
+}
 
+func setupClusterctl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "clusterctl"
 	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
 		return false, nil
 	}
 
-	exists, err := fileExists(filepath.Join(destDir, cliName))
-	if exists || err != nil {
-		return false, err
-	}
-
-	tflog.Debug(ctx, fmt.Sprintf("Downloading cli (%s) from %s", cliName, url))
+	gitOrg := "kubernetes-sigs"
+	gitRepo := "cluster-api"
 
-	err = writeFileFromUrl(url, destDir, cliName)
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
 		return false, err
 	}
 
-	tflog.Trace(ctx, fmt.Sprintf("Testing downloaded cli: %s", cliName))
-
-	cmd := exec.Command(filepath.Join(destDir, cliName), testArgs...)
-	var outb, errb bytes.Buffer
-	cmd.Stdout = &outb
-	cmd.Stderr = &errb
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
 
-	err = cmd.Run()
-	if err != nil {
-		return false, fmt.Errorf("unable to validate downloaded cli: %s, %s", filepath.Join(destDir, cliName), errb.String())
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Validation of cli successful: %s, %s", filepath.Join(destDir, cliName), outb.String()))
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/clusterctl-%s-%s", gitOrg, gitRepo, releaseInfo.TagName, osName, arch)
 
-	return true, err
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
 }
 
-func writeFileFromUrl(url string, destDir string, destFile string) error {
-	out, err := os.OpenFile(filepath.Join(destDir, destFile), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
-	if err != nil {
-		return err
+func setupOdo(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "odo"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
 	}
-	defer func() {
-		if tempErr := out.Close(); tempErr != nil {
-			err = tempErr
-		}
-	}()
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
 	}
-	defer func() {
-		if tempErr := resp.Body.Close(); tempErr != nil {
-			err = tempErr
-		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status retrieving file %s from url: %s, %s", destFile, resp.Status, url)
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
 	}
 
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
-		return err
-	}
+	url := fmt.Sprintf("https://developers.redhat.com/content-gateway/rest/mirror/pub/openshift-v4/clients/odo/latest/odo-%s-%s.tar.gz", osName, arch)
 
-	return err
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
 }
 
-func setupBinaryFromTgz(ctx context.Context, destDir string, cliName string, url string, tgzPath string, testArgs []string, _ string) (bool, error) {
-
-	cliPath, err := exec.LookPath(cliName)
-	if err == nil && len(cliPath) > 0 {
-		tflog.Debug(ctx, fmt.Sprintf("CLI already available in PATH: %s", cliPath))
+func setupCheckov(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "checkov"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
 		return false, nil
 	}
 
-	tflog.Debug(ctx, fmt.Sprintf("Downloading cli (%s) from %s", cliName, url))
-
-	err = extractTarGxFromUrl(ctx, url, tgzPath, destDir, cliName)
+	if envContext.isMusl() {
+		return false, fmt.Errorf("no prebuilt %s binary is available for musl based distributions, install it with pip instead", cliName)
+	}
+	if envContext.isMacOs() && envContext.isArmArch() {
+		return false, fmt.Errorf("no prebuilt %s binary is available for darwin arm64, install it with pip instead", cliName)
+	}
 
-	tflog.Trace(ctx, fmt.Sprintf("Testing downloaded cli: %s", cliName))
+	gitOrg := "bridgecrewio"
+	gitRepo := "checkov"
 
-	cmd := exec.Command(filepath.Join(destDir, cliName), testArgs...)
-	err = cmd.Run()
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
 	if err != nil {
-		err = fmt.Errorf("unable to validate downloaded cli: %s", cliName)
+		return false, err
 	}
 
-	return true, err
-}
-
-func extractTarGxFromUrl(ctx context.Context, url string, tgzPath string, destDir string, cliName string) error {
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
 	}
-	defer func() {
-		if tempErr := resp.Body.Close(); tempErr != nil {
-			err = tempErr
-		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status retrieving cli %s: %s", cliName, resp.Status)
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "X86_64"
 	}
 
-	err = extractTarGz(ctx, resp.Body, tgzPath, destDir, cliName)
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/checkov_%s_%s.zip", gitOrg, gitRepo, releaseInfo.TagName, osName, arch)
 
-	return err
+	return setupBinaryFromZip(ctx, destDir, cliName, url, "dist/checkov", []string{"--version"}, minVersion)
 }
 
-func extractTarGz(ctx context.Context, gzipStream io.Reader, targetFile string, destDir string, destFile string) error {
-	uncompressedStream, err := gzip.NewReader(gzipStream)
-	if err != nil {
-		return err
+func setupInfracost(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "infracost"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "infracost"
+	gitRepo := "infracost"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("infracost-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, filename, []string{"--version"}, minVersion)
+}
+
+func setupSyft(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "syft"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "anchore"
+	gitRepo := "syft"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("syft_%s_%s_%s.tar.gz", shortRelease, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
+}
+
+func setupDive(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "dive"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "wagoodman"
+	gitRepo := "dive"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("dive_%s_%s_%s.tar.gz", shortRelease, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"--version"}, minVersion)
+}
+
+func setupStep(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "step"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "smallstep"
+	gitRepo := "cli"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("step_%s_%s_%s.tar.gz", osName, shortRelease, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, fmt.Sprintf("step_%s/bin/step", shortRelease), []string{"version"}, minVersion)
+}
+
+func setupAge(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "age"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) && cliAlreadyPresent(ctx, destDir, "age-keygen", minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "FiloSottile"
+	gitRepo := "age"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("age-%s-%s-%s.tar.gz", releaseInfo.TagName, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	files := map[string]string{
+		"age/age":        cliName,
+		"age/age-keygen": "age-keygen",
+	}
+
+	return setupBinariesFromTgz(ctx, destDir, url, files, []string{"--version"}, minVersion, "")
+}
+
+func agePresent(ctx context.Context, destDir string, version string) bool {
+	return cliAlreadyPresent(ctx, destDir, "age", version) && cliAlreadyPresent(ctx, destDir, "age-keygen", version)
+}
+
+func setupPopeye(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "popeye"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "derailed"
+	gitRepo := "popeye"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "Darwin"
+	} else {
+		osName = "Linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("popeye_%s_%s.tar.gz", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
+}
+
+func setupKubescape(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "kubescape"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "kubescape"
+	gitRepo := "kubescape"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "macos"
+	} else {
+		osName = "ubuntu"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("kubescape-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupRegctl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "regctl"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "regclient"
+	gitRepo := "regclient"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("regctl-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupTerragrunt(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "terragrunt"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "gruntwork-io"
+	gitRepo := "terragrunt"
+
+	releaseInfo, err := getGitHubRelease(ctx, gitOrg, gitRepo, minVersion)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("terragrunt_%s_%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"--version"}, minVersion)
+}
+
+func setupTerraformDocs(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "terraform-docs"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "terraform-docs"
+	gitRepo := "terraform-docs"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("terraform-docs-%s-%s-%s.tar.gz", releaseInfo.TagName, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"--version"}, minVersion)
+}
+
+func setupPacker(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
+	cliName := "packer"
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
+		return false, nil
+	}
+
+	url := getHashiCorpReleaseUrl(cliName, version, envContext)
+
+	return setupBinaryFromZip(ctx, destDir, cliName, url, cliName, []string{"version"}, version)
+}
+
+func setupNomad(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
+	cliName := "nomad"
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
+		return false, nil
+	}
+
+	url := getHashiCorpReleaseUrl(cliName, version, envContext)
+
+	return setupBinaryFromZip(ctx, destDir, cliName, url, cliName, []string{"version"}, version)
+}
+
+func getHashiCorpReleaseUrl(product string, version string, envContext EnvContext) string {
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else if envContext.isFreeBSD() {
+		osName = "freebsd"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	return fmt.Sprintf("https://releases.hashicorp.com/%s/%s/%s_%s_%s_%s.zip", product, version, product, version, osName, arch)
+}
+
+// ibmcloudCommand creates a command for the ibmcloud cli in destDir that keeps its configuration and
+// plugins in the bin_dir instead of the user's home directory
+func ibmcloudCommand(destDir string, args ...string) *exec.Cmd {
+	cmd := exec.Command(filepath.Join(destDir, "ibmcloud"), args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("IBMCLOUD_HOME=%s", getIBMCloudHome(destDir)))
+
+	return cmd
+}
+
+func getIBMCloudHome(destDir string) string {
+	ibmcloudHome := filepath.Join(destDir, ".ibmcloud")
+	if absPath, err := filepath.Abs(ibmcloudHome); err == nil {
+		ibmcloudHome = absPath
+	}
+
+	return ibmcloudHome
+}
+
+func setupHadolint(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "hadolint"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "hadolint"
+	gitRepo := "hadolint"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "Darwin"
+	} else {
+		osName = "Linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "x86_64"
+	}
+
+	filename := fmt.Sprintf("hadolint-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"--version"}, minVersion)
+}
+
+func setupYamllint(ctx context.Context, destDir string, _ EnvContext, minVersion string) (bool, error) {
+	cliName := "yamllint"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	// yamllint does not publish a standalone binary for any platform so it can only be linked from the PATH
+	return false, fmt.Errorf("%s is not available as a binary, install it with pip (pip install yamllint) so it can be found in the PATH", cliName)
+}
+
+func setupDagger(ctx context.Context, destDir string, envContext EnvContext, version string) (bool, error) {
+	cliName := "dagger"
+	if cliAlreadyPresent(ctx, destDir, cliName, version) {
+		return false, nil
+	}
+
+	release := version
+	if len(release) == 0 {
+		latestVersion, err := getLatestDaggerVersion(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		release = latestVersion
+	}
+	release = strings.TrimPrefix(release, "v")
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	url := fmt.Sprintf("https://dl.dagger.io/dagger/releases/%s/dagger_v%s_%s_%s.tar.gz", release, release, osName, arch)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, version)
+}
+
+func getLatestDaggerVersion(ctx context.Context) (string, error) {
+	url := "https://dl.dagger.io/dagger/latest_version"
+
+	resp, err := httpGetWithRetry(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving latest dagger version from url: %s, %s", resp.Status, url)
+	}
+
+	buf := new(strings.Builder)
+	_, err = io.Copy(buf, resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	version := strings.TrimSpace(buf.String())
+	if !fullVersionRe.MatchString(version) {
+		return "", fmt.Errorf("unexpected dagger version returned from url: %s, %q", url, version)
+	}
+
+	return version, err
+}
+
+func setupEarthly(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "earthly"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "earthly"
+	gitRepo := "earthly"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("earthly-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"--version"}, minVersion)
+}
+
+func setupKo(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "ko"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "ko-build"
+	gitRepo := "ko"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "Darwin"
+	} else {
+		osName = "Linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "x86_64"
+	}
+
+	filename := fmt.Sprintf("ko_%s_%s_%s.tar.gz", shortRelease, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
+}
+
+func setupPluto(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "pluto"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "FairwindsOps"
+	gitRepo := "pluto"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("pluto_%s_%s_%s.tar.gz", shortRelease, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, cliName, []string{"version"}, minVersion)
+}
+
+func setupEtcdctl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "etcdctl"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "etcd-io"
+	gitRepo := "etcd"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	dirName := fmt.Sprintf("etcd-%s-%s-%s", releaseInfo.TagName, osName, arch)
+	archivePath := fmt.Sprintf("%s/etcdctl", dirName)
+
+	// the darwin releases are published as zip files instead of tgz
+	if envContext.isMacOs() {
+		url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s.zip", gitOrg, gitRepo, releaseInfo.TagName, dirName)
+
+		return setupBinaryFromZip(ctx, destDir, cliName, url, archivePath, []string{"version"}, minVersion)
+	}
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s.tar.gz", gitOrg, gitRepo, releaseInfo.TagName, dirName)
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, archivePath, []string{"version"}, minVersion)
+}
+
+func setupCfssl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	if cfsslPresent(ctx, destDir, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "cloudflare"
+	gitRepo := "cfssl"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	cfsslUrl := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/cfssl_%s_%s_%s", gitOrg, gitRepo, releaseInfo.TagName, shortRelease, osName, arch)
+	cfsslResult, err := setupBinary(ctx, destDir, "cfssl", cfsslUrl, []string{"version"}, minVersion)
+	if err != nil {
+		return false, err
+	}
+
+	cfssljsonUrl := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/cfssljson_%s_%s_%s", gitOrg, gitRepo, releaseInfo.TagName, shortRelease, osName, arch)
+	cfssljsonResult, err := setupBinary(ctx, destDir, "cfssljson", cfssljsonUrl, []string{"-version"}, minVersion)
+	if err != nil {
+		return false, err
+	}
+
+	return cfsslResult || cfssljsonResult, nil
+}
+
+func cfsslPresent(ctx context.Context, destDir string, version string) bool {
+	return cliAlreadyPresent(ctx, destDir, "cfssl", version) && cliAlreadyPresent(ctx, destDir, "cfssljson", version)
+}
+
+func setupMkcert(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "mkcert"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "FiloSottile"
+	gitRepo := "mkcert"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("mkcert-%s-%s-%s", releaseInfo.TagName, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"--version"}, minVersion)
+}
+
+func setupArgo(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "argo"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "argoproj"
+	gitRepo := "argo-workflows"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("argo-%s-%s.gz", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromCompressedFile(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupArgoRollouts(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	// installed with the kubectl- prefix so that it is available as a kubectl plugin
+	cliName := "kubectl-argo-rollouts"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "argoproj"
+	gitRepo := "argo-rollouts"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("kubectl-argo-rollouts-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupCalicoctl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "calicoctl"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "projectcalico"
+	gitRepo := "calico"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("calicoctl-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version", "--client"}, minVersion)
+}
+
+func setupVirtctl(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "virtctl"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "kubevirt"
+	gitRepo := "kubevirt"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("virtctl-%s-%s-%s", releaseInfo.TagName, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version", "--client"}, minVersion)
+}
+
+func setupMinikube(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "minikube"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "kubernetes"
+	gitRepo := "minikube"
+
+	releaseInfo, err := getGitHubRelease(ctx, gitOrg, gitRepo, minVersion)
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("minikube-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupDatree(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "datree"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "datreeio"
+	gitRepo := "datree"
+
+	releaseInfo, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+	if err != nil {
+		return false, err
+	}
+
+	shortRelease := strings.Replace(releaseInfo.TagName, "v", "", -1)
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "Darwin"
+	} else {
+		osName = "Linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "x86_64"
+	}
+
+	filename := fmt.Sprintf("datree-cli_%s_%s_%s.zip", shortRelease, osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinaryFromZip(ctx, destDir, cliName, url, "datree", []string{"version"}, minVersion)
+}
+
+func setupS2i(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "s2i"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "openshift"
+	gitRepo := "source-to-image"
+
+	// the release is needed with its assets to find the archive name
+	var releaseInfo *GitHubRelease
+	var err error
+	if len(minVersion) > 0 {
+		releaseInfo, err = getGitHubReleaseByTag(ctx, gitOrg, gitRepo, "v"+strings.TrimPrefix(minVersion, "v"))
+	} else {
+		releaseInfo, err = getLatestGitHubReleaseWithAssets(ctx, gitOrg, gitRepo)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	// only amd64 builds are published for mac
+	var arch string
+	if envContext.isArmArch() && !envContext.isMacOs() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	// the asset name includes the commit sha (e.g. source-to-image-v1.3.9-574a2640-linux-amd64.tar.gz)
+	url, err := matchGitHubReleaseAsset(releaseInfo, "source-to-image-", fmt.Sprintf("-%s-%s.tar.gz", osName, arch))
+	if err != nil {
+		return false, err
+	}
+
+	return setupBinaryFromTgz(ctx, destDir, cliName, url, "./s2i", []string{"version"}, minVersion)
+}
+
+func setupTerraformer(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "terraformer"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	gitOrg := "GoogleCloudPlatform"
+	gitRepo := "terraformer"
+
+	// terraformer releases are tagged without the v prefix (e.g. 0.8.24)
+	var releaseInfo *GitHubRelease
+	if len(minVersion) > 0 {
+		releaseInfo = &GitHubRelease{TagName: strings.TrimPrefix(minVersion, "v")}
+	} else {
+		latestRelease, err := getLatestGitHubRelease(ctx, gitOrg, gitRepo)
+		if err != nil {
+			return false, err
+		}
+
+		releaseInfo = latestRelease
+	}
+
+	var osName string
+	if envContext.isMacOs() {
+		osName = "darwin"
+	} else {
+		osName = "linux"
+	}
+
+	var arch string
+	if envContext.isArmArch() {
+		arch = "arm64"
+	} else {
+		arch = "amd64"
+	}
+
+	filename := fmt.Sprintf("terraformer-all-%s-%s", osName, arch)
+
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/download/%s/%s", gitOrg, gitRepo, releaseInfo.TagName, filename)
+
+	return setupBinary(ctx, destDir, cliName, url, []string{"version"}, minVersion)
+}
+
+func setupBuildah(ctx context.Context, destDir string, envContext EnvContext, minVersion string) (bool, error) {
+	cliName := "buildah"
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	// buildah relies on linux namespaces so there is no build for mac
+	if envContext.isMacOs() {
+		return false, fmt.Errorf("buildah is only available for linux")
+	}
+
+	// the containers/buildah releases only publish the source, so buildah can only be linked from the PATH
+	return false, fmt.Errorf("%s is not available as a binary, install it with the package manager (e.g. apt-get install buildah or dnf install buildah) so it can be found in the PATH", cliName)
+}
+
+func ibmcloudPluginExists(ctx context.Context, destDir string, pluginName string, version string) bool {
+	if crossInstallFromContext(ctx) {
+		return false
+	}
+
+	cmd := ibmcloudCommand(destDir, []string{"plugin", "show", pluginName}...)
+	out, err := cmd.Output()
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("IBM Cloud plugin not already installed: %s", pluginName))
+		return false
+	}
+
+	if len(version) == 0 {
+		return true
+	}
+
+	match := ibmcloudPluginVersionRe.FindStringSubmatch(string(out))
+	if len(match) < 2 {
+		tflog.Debug(ctx, fmt.Sprintf("Unable to find version of IBM Cloud plugin: %s", pluginName))
+		return false
+	}
+
+	if match[1] != version {
+		tflog.Debug(ctx, fmt.Sprintf("IBM Cloud plugin version does not match requested version: %s, %s != %s", pluginName, match[1], version))
+		return false
+	}
+
+	return true
+}
+
+// getGitHubRelease returns the release tagged with the given version or the latest release if no version is provided
+func getGitHubRelease(ctx context.Context, org string, repo string, version string) (*GitHubRelease, error) {
+	if len(version) == 0 {
+		return getLatestGitHubRelease(ctx, org, repo)
+	}
+
+	return &GitHubRelease{TagName: "v" + strings.TrimPrefix(version, "v")}, nil
+}
+
+// getLatestGitHubRelease returns the latest release of the repo. If a tag prefix is provided (e.g. "kustomize/" for
+// repos that release several components) the latest release with a tag starting with the prefix is returned
+func getLatestGitHubRelease(ctx context.Context, org string, repo string, tagPrefix ...string) (*GitHubRelease, error) {
+	if len(tagPrefix) > 0 && len(tagPrefix[0]) > 0 {
+		return getLatestGitHubReleaseWithPrefix(ctx, org, repo, tagPrefix[0])
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", org, repo)
+
+	releaseInfo, statusCode, err := getGitHubApiRelease(ctx, url)
+	if statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests {
+		// unauthenticated requests to the api are rate limited so fall back to the release page
+		tflog.Debug(ctx, fmt.Sprintf("GitHub api request rate limited, falling back to release page: %s/%s, %d", org, repo, statusCode))
+		return getLatestGitHubReleaseFromRedirect(ctx, org, repo)
+	}
+
+	return releaseInfo, err
+}
+
+// getLatestGitHubReleaseWithAssets returns the latest release along with its assets. Unlike getLatestGitHubRelease
+// it doesn't fall back to the release page when the api is rate limited, because the release page only provides
+// the tag, so it must be used by installers that look up the asset names in the release
+func getLatestGitHubReleaseWithAssets(ctx context.Context, org string, repo string) (*GitHubRelease, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", org, repo)
+
+	releaseInfo, statusCode, err := getGitHubApiRelease(ctx, url)
+	if statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("GitHub api request rate limited retrieving the assets of the latest release of %s/%s. Set registry_token to authenticate the request", org, repo)
+	}
+
+	return releaseInfo, err
+}
+
+// getGitHubReleaseByTag returns the release with the given tag, along with its assets
+func getGitHubReleaseByTag(ctx context.Context, org string, repo string, tag string) (*GitHubRelease, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/tags/%s", org, repo, tag)
+
+	releaseInfo, statusCode, err := getGitHubApiRelease(ctx, url)
+	if statusCode == http.StatusForbidden || statusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("GitHub api request rate limited retrieving release %s of %s/%s. Set registry_token to authenticate the request", tag, org, repo)
+	}
+
+	return releaseInfo, err
+}
+
+// getGitHubApiRelease retrieves a single release from the GitHub api. The status code is returned so that the
+// caller can handle rate limiting
+func getGitHubApiRelease(ctx context.Context, url string) (*GitHubRelease, int, error) {
+	resp, err := httpGetWithRetry(ctx, url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("bad status retrieving release from url: %s, %s", resp.Status, url)
+	}
+
+	releaseInfo := &GitHubRelease{}
+	if err := json.NewDecoder(resp.Body).Decode(releaseInfo); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("unable to parse release from url: %s, %w", url, err)
+	}
+
+	if len(releaseInfo.TagName) == 0 {
+		return nil, resp.StatusCode, fmt.Errorf("unable to find tag of release from url: %s", url)
+	}
+
+	return releaseInfo, resp.StatusCode, err
+}
+
+// getLatestGitHubReleaseFromRedirect reads the tag of the latest release from the redirect of the releases/latest
+// page. The assets of the release are not available
+func getLatestGitHubReleaseFromRedirect(ctx context.Context, org string, repo string) (*GitHubRelease, error) {
+	url := fmt.Sprintf("https://github.com/%s/%s/releases/latest", org, repo)
+
+	client := downloaderFromContext(ctx).Client
+	if httpClient, ok := client.(*http.Client); ok {
+		// the redirect is not followed so the tag can be read from the location
+		noRedirectClient := *httpClient
+		noRedirectClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		client = &noRedirectClient
+	}
+
+	req, err := newHttpRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	latestUrl := resp.Header.Get("Location")
+	if len(latestUrl) == 0 {
+		return nil, fmt.Errorf("unable to retrieve location header from url: %s", url)
+	}
+
+	latestTagMatch := regexp.MustCompile(".*/tag/(.+)").FindStringSubmatch(latestUrl)
+	if len(latestTagMatch) < 2 {
+		return nil, fmt.Errorf("unable to parse latest tag from url: %s", latestUrl)
+	}
+
+	releaseInfo := &GitHubRelease{}
+	releaseInfo.TagName = latestTagMatch[1]
+
+	return releaseInfo, err
+}
+
+func getLatestGitHubReleaseWithPrefix(ctx context.Context, org string, repo string, tagPrefix string) (*GitHubRelease, error) {
+	// the other components of a monorepo may have released many times since the last release with the prefix,
+	// so the older pages are searched as well
+	for page := 1; page <= maxGitHubReleasePages; page++ {
+		releases, err := getGitHubReleasesPage(ctx, org, repo, page)
+		if err != nil {
+			return nil, err
+		}
+
+		// releases are returned newest first
+		for _, release := range releases {
+			if release.Draft || release.Prerelease || !strings.HasPrefix(release.TagName, tagPrefix) {
+				continue
+			}
+
+			releaseInfo := release
+			return &releaseInfo, nil
+		}
+
+		if len(releases) < gitHubReleasesPerPage {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("unable to find release with tag prefix %s in %s/%s", tagPrefix, org, repo)
+}
+
+// getLatestStableGitHubRelease returns the release with the highest semver tag that has no pre-release suffix.
+// Unlike getLatestGitHubRelease it doesn't rely on the release the maintainers have marked as latest, which
+// has occasionally been a release candidate for some projects
+func getLatestStableGitHubRelease(ctx context.Context, org string, repo string) (*GitHubRelease, error) {
+	releases, err := getGitHubReleases(ctx, org, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *GitHubRelease
+	var latestVersion *version.Version
+	for _, release := range releases {
+		if release.Draft || release.Prerelease {
+			continue
+		}
+
+		releaseVersion, err := version.NewSemver(release.TagName)
+		if err != nil || len(releaseVersion.Prerelease()) > 0 {
+			continue
+		}
+
+		if latestVersion == nil || releaseVersion.GreaterThan(latestVersion) {
+			releaseInfo := release
+			latest = &releaseInfo
+			latestVersion = releaseVersion
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("unable to find a stable release in %s/%s", org, repo)
+	}
+
+	return latest, nil
+}
+
+func getGitHubReleases(ctx context.Context, org string, repo string) ([]GitHubRelease, error) {
+	return getGitHubReleasesPage(ctx, org, repo, 1)
+}
+
+// getGitHubReleasesPage returns a page of the releases of the repo, newest first
+func getGitHubReleasesPage(ctx context.Context, org string, repo string, page int) ([]GitHubRelease, error) {
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases?per_page=%d&page=%d", org, repo, gitHubReleasesPerPage, page)
+
+	resp, err := httpGetWithRetry(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status retrieving releases from url: %s, %s", resp.Status, url)
+	}
+
+	var releases []GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("unable to parse releases from url: %s, %w", url, err)
+	}
+
+	return releases, err
+}
+
+// matchGitHubReleaseAsset returns the download url of the release asset that best matches the hints (e.g. the os
+// and arch names used by the project), for projects whose asset names can't be built from the tag. An asset
+// matches if its name contains every hint, ignoring case. Checksum and signature files are skipped and the
+// shortest matching name is preferred, since variants of a build (e.g. -musl) add to the name
+func matchGitHubReleaseAsset(release *GitHubRelease, hints ...string) (string, error) {
+	if len(release.Assets) == 0 {
+		return "", fmt.Errorf("no assets available for release %s", release.TagName)
+	}
+
+	var match *GitHubReleaseAsset
+	for i, asset := range release.Assets {
+		name := strings.ToLower(asset.Name)
+
+		if releaseAssetSkipRe.MatchString(name) {
+			continue
+		}
+
+		matchesHints := true
+		for _, hint := range hints {
+			if !strings.Contains(name, strings.ToLower(hint)) {
+				matchesHints = false
+				break
+			}
+		}
+
+		if matchesHints && (match == nil || len(asset.Name) < len(match.Name)) {
+			match = &release.Assets[i]
+		}
+	}
+
+	if match == nil {
+		return "", fmt.Errorf("unable to find asset matching %s in release %s", strings.Join(hints, ", "), release.TagName)
+	}
+
+	return match.BrowserDownloadUrl, nil
+}
+
+func cliAlreadyPresent(ctx context.Context, destDir string, cliName string, minVersion string) bool {
+	cliPath, err := lookPathInBinDir(ctx, destDir, cliName)
+	if err != nil || len(cliPath) == 0 {
+		tflog.Debug(ctx, fmt.Sprintf("CLI not found in path: %s (%s)", cliName, err.Error()))
+		return false
+	}
+
+	if strings.HasPrefix(cliPath, destDir) {
+		tflog.Debug(ctx, fmt.Sprintf("CLI already provided in bin_dir: %s", cliName))
+		return true
+	}
+
+	if crossInstallFromContext(ctx) {
+		tflog.Debug(ctx, fmt.Sprintf("Ignoring cli in PATH that is built for this platform: %s", cliPath))
+		return false
+	}
+
+	if len(minVersion) > 0 {
+		out, err := exec.Command(cliPath, getVersionArgs(cliName)...).Output()
+		if err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("Error getting cli version: %s", cliName))
+		} else {
+			versionString := parseCliVersion(cliName, string(out))
+			if len(versionString) > 0 {
+				tflog.Debug(ctx, fmt.Sprintf("Found version for cli: %s, %s", cliName, versionString))
+
+				currentVersion, err1 := version.NewVersion(versionString)
+				desiredVersion, err2 := version.NewVersion(minVersion)
+
+				if err1 != nil {
+					tflog.Warn(ctx, fmt.Sprintf("Unable to parse version of cli, treating it as not present: %s, %s", cliName, err1.Error()))
+					return false
+				} else if err2 != nil {
+					tflog.Warn(ctx, fmt.Sprintf("Unable to parse required version of cli, treating it as not present: %s, %s", cliName, err2.Error()))
+					return false
+				} else if currentVersion.LessThan(desiredVersion) {
+					tflog.Debug(ctx, fmt.Sprintf("Current cli version is earlier than required version: %s < %s", versionString, minVersion))
+					return false
+				} else if currentVersion.GreaterThanOrEqual(desiredVersion) {
+					tflog.Debug(ctx, fmt.Sprintf("Current cli version is same or newer than required version: %s >= %s", versionString, minVersion))
+				}
+			}
+		}
+	}
+
+	if checkOnlyFromContext(ctx) {
+		tflog.Debug(ctx, fmt.Sprintf("CLI already available in PATH: %s", cliPath))
+		return true
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("CLI already available in PATH: %s. Creating %s in %s", cliPath, linkModeFromContext(ctx), destDir))
+	result, err := createLink(ctx, cliName, filepath.Join(destDir, cliName))
+	if err != nil {
+		tflog.Error(ctx, fmt.Sprintf("Error creating %s: %s, %s", linkModeFromContext(ctx), cliName, err.Error()))
+	}
+
+	return result
+}
+
+// getCliBinary returns the name of the executable that provides the cli in bin_dir. The downloads are recorded in
+// the lock file under this name
+func getCliBinary(cliName string) string {
+	if installer, ok := getInstallers()[cliName]; ok && len(installer.Binary) > 0 {
+		return installer.Binary
+	}
+
+	return cliName
+}
+
+// getBinaryCli returns the name of the cli provided by the executable, the reverse of getCliBinary
+func getBinaryCli(binary string) string {
+	for cliName, installer := range getInstallers() {
+		if installer.Binary == binary {
+			return cliName
+		}
+	}
+
+	return binary
+}
+
+func getVersionArgs(cliName string) []string {
+	if installer, ok := getInstallerRegistry().Get(getBinaryCli(cliName)); ok {
+		return installer.VersionCommand()
+	}
+
+	return []string{"--version"}
+}
+
+// parseCliVersion returns the version of the cli from the output of its version command, using the VersionRe of
+// the installer to find the right version number if there is one
+func parseCliVersion(cliName string, output string) string {
+	if installer, ok := getInstallers()[getBinaryCli(cliName)]; ok && installer.VersionRe != nil {
+		if match := installer.VersionRe.FindStringSubmatch(output); len(match) > 1 {
+			return cleanVersionString(match[1])
+		}
+	}
+
+	return cleanVersionString(output)
+}
+
+// cleanVersionString returns the first version found in the cli output as major.minor.patch. Dotted versions
+// (e.g. 1.7 or 2024.01.02) are preferred over a bare number, and an empty string is returned if no version is found
+func cleanVersionString(value string) string {
+	for _, candidateRe := range []*regexp.Regexp{dottedVersionRe, singleNumberVersionRe} {
+		for _, candidate := range candidateRe.FindAllString(value, -1) {
+			parsedVersion, err := version.NewVersion(candidate)
+			if err != nil {
+				continue
+			}
+
+			segments := parsedVersion.Segments()
+
+			return fmt.Sprintf("%d.%d.%d", segments[0], segments[1], segments[2])
+		}
+	}
+
+	return ""
+}
+
+func setupBinary(ctx context.Context, destDir string, cliName string, url string, testArgs []string, minVersion string) (bool, error) {
+	return setupBinaryWithChecksum(ctx, destDir, cliName, url, testArgs, minVersion, "")
+}
+
+// setupBinaryWithChecksum verifies the sha256 checksum of the downloaded cli, if provided, before it is run
+func setupBinaryWithChecksum(ctx context.Context, destDir string, cliName string, url string, testArgs []string, minVersion string, expectedChecksum string) (bool, error) {
+
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	exists, err := fileExists(filepath.Join(destDir, cliName))
+	if exists || err != nil {
+		return false, err
+	}
+
+	lockFile := lockFileFromContext(ctx)
+	if lockedUrl := lockFile.lockedUrl(ctx, cliName, minVersion, url); lockedUrl != url {
+		// the expected checksum belongs to the original url, the lock file checksum is verified instead
+		url = lockedUrl
+		expectedChecksum = ""
+	}
+
+	if err := checkUrlExists(ctx, url); err != nil {
+		return false, err
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Downloading cli (%s) from %s", cliName, url))
+
+	checksum, err := writeFileFromUrl(ctx, url, destDir, cliName)
+	if err != nil {
+		return false, err
+	}
+
+	if len(expectedChecksum) > 0 && !strings.EqualFold(checksum, expectedChecksum) {
+		_ = os.Remove(filepath.Join(destDir, cliName))
+		return false, fmt.Errorf("checksum of download does not match the published checksum: %s, %s != %s", url, checksum, expectedChecksum)
+	}
+
+	if err := lockFile.verify(cliName, url, checksum); err != nil {
+		_ = os.Remove(filepath.Join(destDir, cliName))
+		return false, err
+	}
+
+	if err := validateDownloadedCli(ctx, destDir, cliName, testArgs); err != nil {
+		return false, err
+	}
+
+	recordInstalledCli(ctx, destDir, cliName, minVersion, url, checksum)
+
+	return true, err
+}
+
+// validateDownloadedCli runs the downloaded cli with the testArgs to make sure it works. The cli can't be run
+// when installing for another platform so only the header of the file is checked
+func validateDownloadedCli(ctx context.Context, destDir string, cliName string, testArgs []string) error {
+	cliPath := filepath.Join(destDir, cliName)
+
+	if crossInstallFromContext(ctx) {
+		tflog.Debug(ctx, fmt.Sprintf("Checking the executable header of cli installed for another platform: %s", cliPath))
+		return verifyExecutableHeader(cliPath)
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("Testing downloaded cli: %s", cliName))
+
+	cmd := exec.Command(cliPath, testArgs...)
+	var outb, errb bytes.Buffer
+	cmd.Stdout = &outb
+	cmd.Stderr = &errb
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unable to validate downloaded cli: %s, %s", cliPath, errb.String())
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Validation of cli successful: %s, %s", cliPath, outb.String()))
+
+	return nil
+}
+
+// verifyExecutableHeader checks that the file starts with the magic bytes of an ELF or Mach-O executable
+func verifyExecutableHeader(cliPath string) error {
+	file, err := os.Open(cliPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if tmpError := file.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return fmt.Errorf("unable to validate downloaded cli, the file is empty or truncated: %s", cliPath)
+	}
+
+	for _, magic := range executableMagicBytes {
+		if bytes.Equal(header, magic) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unable to validate downloaded cli, the file is not an executable: %s", cliPath)
+}
+
+// writeFileFromUrl downloads the url to destFile and returns the sha256 checksum of the contents. Interrupted
+// downloads are resumed where the server supports it
+func writeFileFromUrl(ctx context.Context, url string, destDir string, destFile string) (string, error) {
+	destPath := filepath.Join(destDir, destFile)
+
+	if err := downloadWithResume(ctx, url, destPath); err != nil {
+		return "", err
+	}
+
+	// the checksum is calculated from the completed file since a resumed download arrives in pieces
+	file, err := os.Open(destPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tempErr := file.Close(); tempErr != nil {
+			err = tempErr
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), err
+}
+
+func setupBinaryFromTgz(ctx context.Context, destDir string, cliName string, url string, tgzPath string, testArgs []string, minVersion string) (bool, error) {
+	return setupBinariesFromTgz(ctx, destDir, url, map[string]string{tgzPath: cliName}, testArgs, minVersion, "")
+}
+
+// setupBinaryFromTgzWithChecksum verifies the sha256 checksum of the downloaded archive before extracting the cli
+func setupBinaryFromTgzWithChecksum(ctx context.Context, destDir string, cliName string, url string, tgzPath string, testArgs []string, minVersion string, checksum string) (bool, error) {
+	return setupBinariesFromTgz(ctx, destDir, url, map[string]string{tgzPath: cliName}, testArgs, minVersion, checksum)
+}
+
+// setupBinariesFromTgz extracts each of the files (tgz path -> cli name) that are not already available
+// in the PATH from a single download of the archive. If expectedChecksum is provided the archive is verified
+// against it before anything is extracted
+func setupBinariesFromTgz(ctx context.Context, destDir string, url string, files map[string]string, testArgs []string, minVersion string, expectedChecksum string) (bool, error) {
+
+	missingFiles := make(map[string]string)
+	for tgzPath, cliName := range files {
+		if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+			continue
+		}
+
+		missingFiles[tgzPath] = cliName
+	}
+
+	if len(missingFiles) == 0 {
+		return false, nil
+	}
+
+	cliNames := mapValues(missingFiles)
+
+	// every cli extracted from the archive is recorded in the lock file, so any of them can provide the url
+	lockFile := lockFileFromContext(ctx)
+	for _, cliName := range cliNames {
+		if lockedUrl := lockFile.lockedUrl(ctx, cliName, minVersion, url); lockedUrl != url {
+			// the expected checksum belongs to the original url, the lock file checksum is verified instead
+			url = lockedUrl
+			expectedChecksum = ""
+			break
+		}
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Downloading clis (%s) from %s", strings.Join(cliNames, ", "), url))
+
+	checksum, err := extractTarGxFromUrl(ctx, url, missingFiles, destDir, expectedChecksum)
+	if err != nil {
+		return false, err
+	}
+
+	for _, lockedCliName := range cliNames {
+		if err := lockFile.verify(lockedCliName, url, checksum); err != nil {
+			for _, cliName := range cliNames {
+				_ = os.Remove(filepath.Join(destDir, cliName))
+			}
+			return false, err
+		}
+	}
+
+	for _, cliName := range cliNames {
+		if err := validateDownloadedCli(ctx, destDir, cliName, testArgs); err != nil {
+			return false, err
+		}
+
+		recordInstalledCli(ctx, destDir, cliName, minVersion, url, checksum)
+	}
+
+	return true, nil
+}
+
+// extractTarGxFromUrl extracts the files from the archive at the url and returns the sha256 checksum of the archive
+func extractTarGxFromUrl(ctx context.Context, url string, files map[string]string, destDir string, expectedChecksum string) (string, error) {
+
+	if err := checkUrlExists(ctx, url); err != nil {
+		return "", err
+	}
+
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tempErr := resp.Body.Close(); tempErr != nil {
+			err = tempErr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving clis %s: %s", strings.Join(mapValues(files), ", "), resp.Status)
+	}
+
+	var archive = newProgressReader(ctx, resp, url)
+	if len(expectedChecksum) > 0 {
+		// the archive is buffered so the checksum can be verified before any of it is extracted
+		data, err := io.ReadAll(archive)
+		if err != nil {
+			return "", err
+		}
+
+		if err := verifyChecksum(url, data, expectedChecksum); err != nil {
+			return "", err
+		}
+
+		archive = bytes.NewReader(data)
+	}
+
+	hash := sha256.New()
+	body := io.TeeReader(archive, hash)
+
+	err = extractTarGz(ctx, body, files, destDir)
+	if err != nil {
+		return "", err
+	}
+
+	// read the rest of the archive so the checksum covers the whole file
+	if _, err = io.Copy(io.Discard, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), err
+}
+
+// extractTarGz writes each of the target files (tgz path -> dest file name) found in the archive to destDir
+func extractTarGz(ctx context.Context, gzipStream io.Reader, targetFiles map[string]string, destDir string) error {
+	uncompressedStream, err := gzip.NewReader(gzipStream)
+	if err != nil {
+		return err
 	}
 
 	tarReader := tar.NewReader(uncompressedStream)
 
-	for true {
+	extracted := make(map[string]bool)
+	for len(extracted) < len(targetFiles) {
 		header, err := tarReader.Next()
 
 		if err == io.EOF {
@@ -1062,26 +3421,37 @@ func extractTarGz(ctx context.Context, gzipStream io.Reader, targetFile string,
 		case tar.TypeDir:
 			continue
 		case tar.TypeReg:
-			if header.Name != targetFile {
+			destFile, ok := targetFiles[header.Name]
+			if !ok {
 				tflog.Trace(ctx, fmt.Sprintf("Skipping file in tgz: %s", header.Name))
 				continue
 			}
 
 			tflog.Debug(ctx, fmt.Sprintf("Extracting file from tgz to destination: %s -> %s", header.Name, filepath.Join(destDir, destFile)))
-			err = extractFileFromTar(ctx, tarReader, destDir, destFile)
+			if err := extractFileFromTar(ctx, tarReader, destDir, destFile); err != nil {
+				return err
+			}
+
+			extracted[header.Name] = true
 
 		default:
 			tflog.Error(ctx, fmt.Sprintf("unknown type: %b in %s", header.Typeflag, header.Name))
 		}
 	}
 
-	return err
+	for targetFile := range targetFiles {
+		if !extracted[targetFile] {
+			return fmt.Errorf("unable to find %s in tgz archive", targetFile)
+		}
+	}
+
+	return nil
 }
 
 func extractFileFromTar(ctx context.Context, tarReader io.Reader, destDir string, destFile string) error {
 	outFileName := filepath.Join(destDir, destFile)
 
-	outFile, err := os.OpenFile(outFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0777)
+	outFile, err := os.OpenFile(outFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
 	if err != nil {
 		tflog.Error(ctx, fmt.Sprintf("Failed to create file: %s", outFileName))
 		return err
@@ -1100,9 +3470,263 @@ func extractFileFromTar(ctx context.Context, tarReader io.Reader, destDir string
 	return err
 }
 
-func checkCurrentVersion(ctx context.Context, cli string, versionArgs []string, versionRegEx string) bool {
+func setupBinaryFromZip(ctx context.Context, destDir string, cliName string, url string, zipPath string, testArgs []string, minVersion string) (bool, error) {
+	return setupBinaryFromZipWithChecksum(ctx, destDir, cliName, url, zipPath, testArgs, minVersion, "")
+}
+
+// setupBinaryFromZipWithChecksum verifies the sha256 checksum of the downloaded archive, if provided, before
+// extracting the cli
+func setupBinaryFromZipWithChecksum(ctx context.Context, destDir string, cliName string, url string, zipPath string, testArgs []string, minVersion string, expectedChecksum string) (bool, error) {
+
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	lockFile := lockFileFromContext(ctx)
+	if lockedUrl := lockFile.lockedUrl(ctx, cliName, minVersion, url); lockedUrl != url {
+		// the expected checksum belongs to the original url, the lock file checksum is verified instead
+		url = lockedUrl
+		expectedChecksum = ""
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Downloading cli (%s) from %s", cliName, url))
+
+	checksum, err := extractZipFromUrl(ctx, url, zipPath, destDir, cliName, expectedChecksum)
+	if err != nil {
+		return false, err
+	}
+
+	if err := lockFile.verify(cliName, url, checksum); err != nil {
+		_ = os.Remove(filepath.Join(destDir, cliName))
+		return false, err
+	}
+
+	if err := validateDownloadedCli(ctx, destDir, cliName, testArgs); err != nil {
+		return false, err
+	}
+
+	recordInstalledCli(ctx, destDir, cliName, minVersion, url, checksum)
+
+	return true, nil
+}
+
+// extractZipFromUrl extracts the file from the archive at the url and returns the sha256 checksum of the archive.
+// If expectedChecksum is provided the archive is verified against it before the file is extracted
+func extractZipFromUrl(ctx context.Context, url string, zipPath string, destDir string, cliName string, expectedChecksum string) (string, error) {
+
+	if err := checkUrlExists(ctx, url); err != nil {
+		return "", err
+	}
+
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tempErr := resp.Body.Close(); tempErr != nil {
+			err = tempErr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving cli %s: %s", cliName, resp.Status)
+	}
+
+	// zip archives need random access so the contents are buffered in memory
+	body, err := io.ReadAll(newProgressReader(ctx, resp, url))
+	if err != nil {
+		return "", err
+	}
+
+	if len(expectedChecksum) > 0 {
+		if err := verifyChecksum(url, body, expectedChecksum); err != nil {
+			return "", err
+		}
+	}
+
+	err = extractZip(ctx, bytes.NewReader(body), int64(len(body)), zipPath, destDir, cliName)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := sha256.Sum256(body)
+
+	return hex.EncodeToString(checksum[:]), err
+}
+
+func extractZip(ctx context.Context, zipStream io.ReaderAt, size int64, targetFile string, destDir string, destFile string) error {
+	zipReader, err := zip.NewReader(zipStream, size)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range zipReader.File {
+		if file.Name != targetFile {
+			tflog.Trace(ctx, fmt.Sprintf("Skipping file in zip: %s", file.Name))
+			continue
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Extracting file from zip to destination: %s -> %s", file.Name, filepath.Join(destDir, destFile)))
+
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+
+		err = extractFileFromTar(ctx, reader, destDir, destFile)
+		if tmpError := reader.Close(); err == nil {
+			err = tmpError
+		}
+
+		return err
+	}
+
+	return fmt.Errorf("unable to find %s in zip archive", targetFile)
+}
+
+// setupBinaryFromCompressedFile installs a cli that is published as a single compressed binary (.gz, .bz2 or .xz)
+// rather than an archive
+func setupBinaryFromCompressedFile(ctx context.Context, destDir string, cliName string, url string, testArgs []string, minVersion string) (bool, error) {
+	return setupBinaryFromCompressedFileWithChecksum(ctx, destDir, cliName, url, testArgs, minVersion, "")
+}
+
+// setupBinaryFromCompressedFileWithChecksum verifies the sha256 checksum of the downloaded file, if provided, before
+// decompressing the cli
+func setupBinaryFromCompressedFileWithChecksum(ctx context.Context, destDir string, cliName string, url string, testArgs []string, minVersion string, expectedChecksum string) (bool, error) {
+
+	if cliAlreadyPresent(ctx, destDir, cliName, minVersion) {
+		return false, nil
+	}
+
+	lockFile := lockFileFromContext(ctx)
+	if lockedUrl := lockFile.lockedUrl(ctx, cliName, minVersion, url); lockedUrl != url {
+		// the expected checksum belongs to the original url, the lock file checksum is verified instead
+		url = lockedUrl
+		expectedChecksum = ""
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("Downloading cli (%s) from %s", cliName, url))
+
+	checksum, err := decompressSingleFileFromUrl(ctx, url, destDir, cliName, expectedChecksum)
+	if err != nil {
+		return false, err
+	}
+
+	if err := lockFile.verify(cliName, url, checksum); err != nil {
+		_ = os.Remove(filepath.Join(destDir, cliName))
+		return false, err
+	}
+
+	if err := validateDownloadedCli(ctx, destDir, cliName, testArgs); err != nil {
+		return false, err
+	}
+
+	recordInstalledCli(ctx, destDir, cliName, minVersion, url, checksum)
+
+	return true, nil
+}
+
+// decompressSingleFileFromUrl decompresses the file at the url into destFile and returns the sha256 checksum
+// of the compressed file. If expectedChecksum is provided the file is verified against it before it is decompressed
+func decompressSingleFileFromUrl(ctx context.Context, url string, destDir string, destFile string, expectedChecksum string) (string, error) {
+
+	if err := checkUrlExists(ctx, url); err != nil {
+		return "", err
+	}
+
+	resp, err := httpGet(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if tempErr := resp.Body.Close(); tempErr != nil {
+			err = tempErr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status retrieving cli %s: %s", destFile, resp.Status)
+	}
+
+	var compressed = newProgressReader(ctx, resp, url)
+	if len(expectedChecksum) > 0 {
+		// the file is buffered so the checksum can be verified before any of it is decompressed
+		data, err := io.ReadAll(compressed)
+		if err != nil {
+			return "", err
+		}
+
+		if err := verifyChecksum(url, data, expectedChecksum); err != nil {
+			return "", err
+		}
+
+		compressed = bytes.NewReader(data)
+	}
+
+	hash := sha256.New()
+	body := io.TeeReader(compressed, hash)
+
+	err = decompressSingleFile(ctx, body, path.Ext(url), destDir, destFile)
+	if err != nil {
+		return "", err
+	}
+
+	// read the rest of the file so the checksum covers all of it
+	if _, err = io.Copy(io.Discard, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), err
+}
+
+// decompressSingleFile writes the decompressed stream to destFile. The compression is identified by the
+// extension of the downloaded file (.gz, .bz2 or .xz)
+func decompressSingleFile(ctx context.Context, stream io.Reader, extension string, destDir string, destFile string) error {
+	tflog.Debug(ctx, fmt.Sprintf("Decompressing %s file to destination: %s", extension, filepath.Join(destDir, destFile)))
+
+	switch extension {
+	case ".gz":
+		uncompressedStream, err := gzip.NewReader(stream)
+		if err != nil {
+			return err
+		}
+
+		return extractFileFromTar(ctx, uncompressedStream, destDir, destFile)
+	case ".bz2":
+		return extractFileFromTar(ctx, bzip2.NewReader(stream), destDir, destFile)
+	case ".xz":
+		// there is no xz support in the standard library so the xz cli is used to decompress the stream
+		xzPath, err := exec.LookPath("xz")
+		if err != nil {
+			return fmt.Errorf("the xz cli is required to decompress %s: %w", destFile, err)
+		}
+
+		cmd := exec.Command(xzPath, "-dc")
+		cmd.Stdin = stream
+
+		uncompressedStream, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		if err := extractFileFromTar(ctx, uncompressedStream, destDir, destFile); err != nil {
+			_ = cmd.Wait()
+			return err
+		}
+
+		return cmd.Wait()
+	default:
+		return fmt.Errorf("unsupported compression for cli %s: %s", destFile, extension)
+	}
+}
+
+func checkCurrentVersion(ctx context.Context, destDir string, cli string, versionArgs []string, versionRegEx string) bool {
 
-	cliPath, _ := exec.LookPath(cli)
+	cliPath, _ := lookPathInBinDir(ctx, destDir, cli)
 	if len(cliPath) == 0 {
 		return false
 	}
@@ -1121,41 +3745,121 @@ func checkCurrentVersion(ctx context.Context, cli string, versionArgs []string,
 
 	tflog.Debug(ctx, fmt.Sprintf("Version output for cli: %s, %s", cli, stdout))
 
-	r := regexp.MustCompile(`.*([0-9]+[.][0-9]+[.][0-9]+).*`)
-	matches := r.FindStringSubmatch(stdout)
-	if len(matches) < 2 {
+	version := parseCliVersion(cli, stdout)
+	if len(version) == 0 {
 		return false
 	}
 
-	version := matches[1]
-
 	tflog.Debug(ctx, fmt.Sprintf("Found version string: %s, %s", cli, version))
 
 	versionRegex := regexp.MustCompile(versionRegEx)
 	return versionRegex.MatchString(version)
 }
 
+// createLink provides the cli from the PATH in bin_dir as either a symlink or a copy depending on the link mode
+func createLink(ctx context.Context, cli string, linkTo string) (bool, error) {
+	if linkModeFromContext(ctx) == linkModeCopy {
+		return createCopy(ctx, cli, linkTo)
+	}
+
+	return createSymLink(cli, linkTo)
+}
+
+func createCopy(ctx context.Context, cli string, copyTo string) (bool, error) {
+
+	exists, err := fileExists(copyTo)
+	if exists || err != nil {
+		return false, err
+	}
+
+	cliPath, err := lookPathInBinDir(ctx, filepath.Dir(copyTo), cli)
+	if err != nil {
+		return false, err
+	}
+
+	if cliPath == copyTo {
+		return false, nil
+	}
+
+	err = copyFile(cliPath, copyTo)
+
+	return true, err
+}
+
 func createSymLink(cli string, linkTo string) (bool, error) {
 
 	exists, err := fileExists(linkTo)
-	if exists || err != nil {
+	if err != nil {
 		return false, err
 	}
 
-	cliPath, err := exec.LookPath(cli)
+	target := ""
+	if exists {
+		target, err = os.Readlink(linkTo)
+		if err != nil {
+			// not a symlink, e.g. a previously downloaded cli, so leave it alone
+			return false, nil
+		}
+	}
+
+	// the link itself may be first in the PATH so look for the cli outside the link's directory
+	cliPath, err := lookPathOutside(cli, filepath.Dir(linkTo))
 	if err != nil {
 		return false, err
 	}
 
-	if cliPath == linkTo {
+	if cliPath == linkTo || cliPath == target {
 		return false, nil
 	}
 
-	err = os.Symlink(cliPath, linkTo)
+	if !exists {
+		err = os.Symlink(cliPath, linkTo)
+
+		return true, err
+	}
+
+	// replace the link pointing at the wrong target by renaming a new link over it
+	tmpLink := linkTo + ".tmp"
+	if err := os.Remove(tmpLink); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	if err := os.Symlink(cliPath, tmpLink); err != nil {
+		return false, err
+	}
+
+	err = os.Rename(tmpLink, linkTo)
 
 	return true, err
 }
 
+// lookPathOutside searches the PATH for an executable cli, skipping the excluded directory
+func lookPathOutside(cli string, excludeDir string) (string, error) {
+	excludeDir, err := filepath.Abs(excludeDir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if len(dir) == 0 {
+			dir = "."
+		}
+
+		absDir, err := filepath.Abs(dir)
+		if err != nil || absDir == excludeDir {
+			continue
+		}
+
+		cliPath := filepath.Join(dir, cli)
+		info, err := os.Stat(cliPath)
+		if err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+			return cliPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: %w", cli, exec.ErrNotFound)
+}
+
 func fileExists(filename string) (bool, error) {
 	_, err := os.Lstat(filename)
 	if err == nil {
@@ -0,0 +1,739 @@
+package clis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+func TestSetupBinaryFromFixture(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	url := "https://github.com/argoproj/argo-cd/releases/download/v2.10.1/argocd-linux-amd64"
+	fixture.add(url, fixtureCli("argocd: v2.10.1+a79e0ea"))
+
+	installed, err := setupBinary(ctx, binDir, "argocd", url, []string{"version", "--client"}, "2.10.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !installed {
+		t.Errorf("expected argocd to be installed")
+	}
+
+	if _, err := os.Stat(filepath.Join(binDir, "argocd")); err != nil {
+		t.Errorf("expected argocd in bin_dir: %s", err.Error())
+	}
+}
+
+func TestSetupBinaryFromTgzFixture(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	url := "https://get.helm.sh/helm-v3.8.2-linux-amd64.tar.gz"
+	fixture.add(url, fixtureTgz(t, map[string][]byte{
+		"linux-amd64/helm":   fixtureCli(`version.BuildInfo{Version:"v3.8.2", GitCommit:"6e3701e", GoVersion:"go1.17.5"}`),
+		"linux-amd64/README": []byte("helm"),
+	}))
+
+	installed, err := setupBinaryFromTgz(ctx, binDir, "helm", url, "linux-amd64/helm", []string{"version"}, "3.8.2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !installed {
+		t.Errorf("expected helm to be installed")
+	}
+
+	if _, err := os.Stat(filepath.Join(binDir, "helm")); err != nil {
+		t.Errorf("expected helm in bin_dir: %s", err.Error())
+	}
+	if _, err := os.Stat(filepath.Join(binDir, "README")); err == nil {
+		t.Errorf("expected only helm to be extracted from the archive")
+	}
+}
+
+func TestSetupBinaryFromTgzFixtureChecksumMismatch(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	url := "https://get.helm.sh/helm-v3.8.2-linux-amd64.tar.gz"
+	fixture.add(url, fixtureTgz(t, map[string][]byte{
+		"linux-amd64/helm": fixtureCli(`version.BuildInfo{Version:"v3.8.2"}`),
+	}))
+
+	_, err := setupBinaryFromTgzWithChecksum(ctx, binDir, "helm", url, "linux-amd64/helm", []string{"version"}, "3.8.2", fixtureChecksum([]byte("other")))
+	if err == nil {
+		t.Fatalf("expected the checksum mismatch to be reported")
+	}
+
+	if _, err := os.Stat(filepath.Join(binDir, "helm")); err == nil {
+		t.Errorf("expected helm to be removed after the checksum mismatch")
+	}
+}
+
+func TestSetupBinaryFromZipFixture(t *testing.T) {
+	url := "https://releases.hashicorp.com/packer/1.11.2/packer_1.11.2_linux_amd64.zip"
+	archive := fixtureZip(t, map[string][]byte{
+		"packer": fixtureCli("Packer v1.11.2"),
+	})
+
+	tests := []struct {
+		name              string
+		archive           []byte
+		checksum          string
+		expectedInstalled bool
+		expectedFile      bool
+	}{
+		{name: "checksum", archive: archive, checksum: fixtureChecksum(archive), expectedInstalled: true, expectedFile: true},
+		{name: "checksum mismatch", archive: archive, checksum: fixtureChecksum([]byte("other"))},
+		{name: "invalid cli", archive: fixtureZip(t, map[string][]byte{"packer": []byte("not a cli")}), expectedFile: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			ctx := fixtureContext(t, fixture)
+			binDir := t.TempDir()
+
+			fixture.add(url, test.archive)
+
+			installed, err := setupBinaryFromZipWithChecksum(ctx, binDir, "packer", url, "packer", []string{"version"}, "1.11.2", test.checksum)
+			if test.expectedInstalled && err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !test.expectedInstalled && err == nil {
+				t.Fatalf("expected the install to fail")
+			}
+			if installed != test.expectedInstalled {
+				t.Errorf("expected installed to be %t", test.expectedInstalled)
+			}
+
+			// nothing is extracted from an archive that fails the checksum
+			if _, err := os.Stat(filepath.Join(binDir, "packer")); (err == nil) != test.expectedFile {
+				t.Errorf("expected packer in bin_dir to be %t", test.expectedFile)
+			}
+		})
+	}
+}
+
+func TestSetupBinaryFromCompressedFileFixture(t *testing.T) {
+	url := "https://github.com/argoproj/argo-workflows/releases/download/v3.5.5/argo-linux-amd64.gz"
+	compressed := fixtureGz(t, fixtureCli("argo: v3.5.5"))
+
+	tests := []struct {
+		name              string
+		compressed        []byte
+		checksum          string
+		expectedInstalled bool
+		expectedFile      bool
+	}{
+		{name: "checksum", compressed: compressed, checksum: fixtureChecksum(compressed), expectedInstalled: true, expectedFile: true},
+		{name: "checksum mismatch", compressed: compressed, checksum: fixtureChecksum([]byte("other"))},
+		{name: "invalid cli", compressed: fixtureGz(t, []byte("not a cli")), expectedFile: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			ctx := fixtureContext(t, fixture)
+			binDir := t.TempDir()
+
+			fixture.add(url, test.compressed)
+
+			installed, err := setupBinaryFromCompressedFileWithChecksum(ctx, binDir, "argo", url, []string{"version"}, "3.5.5", test.checksum)
+			if test.expectedInstalled && err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !test.expectedInstalled && err == nil {
+				t.Fatalf("expected the install to fail")
+			}
+			if installed != test.expectedInstalled {
+				t.Errorf("expected installed to be %t", test.expectedInstalled)
+			}
+
+			// nothing is decompressed from a file that fails the checksum
+			if _, err := os.Stat(filepath.Join(binDir, "argo")); (err == nil) != test.expectedFile {
+				t.Errorf("expected argo in bin_dir to be %t", test.expectedFile)
+			}
+		})
+	}
+}
+
+func TestClisCheckReadFromFixtures(t *testing.T) {
+	fixture := newFixtureServer(t)
+	binDir := t.TempDir()
+
+	argocdUrl := fmt.Sprintf("https://github.com/argoproj/argo-cd/releases/download/v2.10.1/argocd-%s-%s", runtime.GOOS, runtime.GOARCH)
+	fixture.add(argocdUrl, fixtureCli("argocd: v2.10.1+a79e0ea"))
+
+	helmFile := fmt.Sprintf("helm-v3.8.2-%s-%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	helmTgz := fixtureTgz(t, map[string][]byte{
+		fmt.Sprintf("%s-%s/helm", runtime.GOOS, runtime.GOARCH): fixtureCli(`version.BuildInfo{Version:"v3.8.2", GitCommit:"6e3701e", GoVersion:"go1.17.5"}`),
+	})
+	fixture.add("https://get.helm.sh/"+helmFile, helmTgz)
+	fixture.add("https://get.helm.sh/"+helmFile+".sha256sum", []byte(fixtureChecksum(helmTgz)+"  "+helmFile+"\n"))
+
+	raw := map[string]interface{}{
+		"clis":             []interface{}{"argocd@2.10.1", "helm"},
+		"install_defaults": false,
+	}
+
+	d, diags := readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	installed := d.Get("installed").(map[string]interface{})
+	versions := d.Get("versions").(map[string]interface{})
+	for name, version := range map[string]string{"argocd": "2.10.1", "helm": "3.8.2"} {
+		if installed[name] != true {
+			t.Errorf("expected %s to be installed: %v", name, installed)
+		}
+		if versions[name] != version {
+			t.Errorf("expected version of %s to be %s: %v", name, version, versions)
+		}
+	}
+
+	// the clis are now in bin_dir so nothing is looked up or downloaded
+	requests := fixture.requestCount()
+
+	d, diags = readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if count := fixture.requestCount(); count != requests {
+		t.Errorf("expected no requests when the clis are present, got %d", count-requests)
+	}
+
+	installed = d.Get("installed").(map[string]interface{})
+	if installed["argocd"] != false || installed["helm"] != false {
+		t.Errorf("expected nothing to be installed: %v", installed)
+	}
+}
+
+func TestClisCheckReadArgoRollouts(t *testing.T) {
+	fixture := newFixtureServer(t)
+	binDir := t.TempDir()
+
+	// argo-rollouts is installed as the kubectl-argo-rollouts plugin
+	fixture.add("https://api.github.com/repos/argoproj/argo-rollouts/releases/latest", []byte(`{"tag_name": "v1.6.6"}`))
+	fixture.add(
+		fmt.Sprintf("https://github.com/argoproj/argo-rollouts/releases/download/v1.6.6/kubectl-argo-rollouts-%s-%s", runtime.GOOS, runtime.GOARCH),
+		fixtureCli("kubectl-argo-rollouts: v1.6.6+737ca89"),
+	)
+
+	raw := map[string]interface{}{
+		"clis":             []interface{}{"argo-rollouts"},
+		"install_defaults": false,
+	}
+
+	d, diags := readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if installed := d.Get("installed").(map[string]interface{}); installed["argo-rollouts"] != true {
+		t.Errorf("expected argo-rollouts to be installed: %v", installed)
+	}
+	if versions := d.Get("versions").(map[string]interface{}); versions["argo-rollouts"] != "1.6.6" {
+		t.Errorf("expected version of argo-rollouts to be 1.6.6: %v", versions)
+	}
+
+	// the version is pinned in the lock file for the next read
+	lockFile, err := loadLockFile(binDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if version, ok := lockFile.lockedVersion(getCliBinary("argo-rollouts"), ""); !ok || version != "1.6.6" {
+		t.Errorf("expected argo-rollouts 1.6.6 in the lock file, got %s", version)
+	}
+}
+
+func TestClisCheckReadLatestVersionWarning(t *testing.T) {
+	fixture := newFixtureServer(t)
+	binDir := t.TempDir()
+
+	fixture.add("https://api.github.com/repos/argoproj/argo-cd/releases", []byte(`[{"tag_name": "v2.10.1"}]`))
+	fixture.add(
+		fmt.Sprintf("https://github.com/argoproj/argo-cd/releases/download/v2.10.1/argocd-%s-%s", runtime.GOOS, runtime.GOARCH),
+		fixtureCli("argocd: v2.10.1+a79e0ea"),
+	)
+
+	raw := map[string]interface{}{
+		"clis":             []interface{}{"argocd"},
+		"install_defaults": false,
+	}
+
+	_, diags := readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("expected a warning for the unpinned argocd: %v", diags)
+	}
+
+	// the next install uses the version in the lock file instead of the latest release
+	if err := os.Remove(filepath.Join(binDir, "argocd")); err != nil {
+		t.Fatal(err)
+	}
+
+	d, diags := readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if len(diags) != 0 {
+		t.Errorf("expected no warning for the locked argocd: %v", diags)
+	}
+
+	if installed := d.Get("installed").(map[string]interface{}); installed["argocd"] != true {
+		t.Errorf("expected argocd to be installed: %v", installed)
+	}
+}
+
+func TestClisCheckReadAllPresent(t *testing.T) {
+	fixture := newFixtureServer(t)
+	binDir := t.TempDir()
+
+	for name, versionOutput := range map[string]string{
+		"argocd": "argocd: v2.10.1+a79e0ea",
+		"helm":   `version.BuildInfo{Version:"v3.8.2", GitCommit:"6e3701e", GoVersion:"go1.17.5"}`,
+	} {
+		if err := os.WriteFile(filepath.Join(binDir, name), fixtureCli(versionOutput), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	raw := map[string]interface{}{
+		"clis":             []interface{}{"argocd@2.10.1", "helm"},
+		"install_defaults": false,
+	}
+
+	// the fixture server has no files, so any lookup or download would fail the read
+	d, diags := readClisCheck(t, fixture, binDir, raw)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if count := fixture.requestCount(); count != 0 {
+		t.Errorf("expected no requests when every cli is present, got %d", count)
+	}
+
+	installed := d.Get("installed").(map[string]interface{})
+	if installed["argocd"] != false || installed["helm"] != false {
+		t.Errorf("expected nothing to be installed: %v", installed)
+	}
+}
+
+func TestSetupArgoCDAlreadyPresent(t *testing.T) {
+	for _, version := range []string{"", "2.10.1"} {
+		t.Run("version "+version, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			ctx := fixtureContext(t, fixture)
+			binDir := t.TempDir()
+
+			if err := os.WriteFile(filepath.Join(binDir, "argocd"), fixtureCli("argocd: v2.10.1+a79e0ea"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			// the read adds bin_dir to the PATH before running the installers
+			t.Setenv("PATH", binDir)
+
+			installed, err := setupArgoCD(ctx, binDir, fixtureEnvContext(), version)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if installed {
+				t.Errorf("expected the argocd in bin_dir to be used")
+			}
+
+			// neither the latest release nor the requested release is looked up
+			if count := fixture.requestCount(); count != 0 {
+				t.Errorf("expected no requests when argocd is present, got %d", count)
+			}
+		})
+	}
+}
+
+func TestYqPresent(t *testing.T) {
+	tests := []struct {
+		name     string
+		clis     map[string]string
+		expected bool
+	}{
+		{name: "yq4 as yq", clis: map[string]string{"yq3": "yq version 3.4.1", "yq": "yq (https://github.com/mikefarah/yq/) version v4.40.5"}, expected: false},
+		{name: "yq3 and yq4", clis: map[string]string{"yq3": "yq version 3.4.1", "yq4": "yq (https://github.com/mikefarah/yq/) version v4.40.5"}, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			ctx := fixtureContext(t, fixture)
+
+			pathDir := t.TempDir()
+			for name, versionOutput := range test.clis {
+				if err := os.WriteFile(filepath.Join(pathDir, name), fixtureCli(versionOutput), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PATH", pathDir)
+
+			if present := yqPresent(ctx, t.TempDir(), ""); present != test.expected {
+				t.Errorf("expected present to be %t", test.expected)
+			}
+		})
+	}
+}
+
+func TestSetupBuildah(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	// no binary is published so nothing is looked up or downloaded
+	_, err := setupBuildah(ctx, binDir, fixtureEnvContext(), "")
+	if err == nil || !strings.Contains(err.Error(), "not available as a binary") {
+		t.Errorf("expected buildah to be reported as not available: %v", err)
+	}
+	if count := fixture.requestCount(); count != 0 {
+		t.Errorf("expected no requests for buildah, got %d", count)
+	}
+
+	// a buildah installed with the package manager is used
+	pathDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pathDir, "buildah"), fixtureCli("buildah version 1.35.3 (image-spec 1.1.0, runtime-spec 1.1.0)"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+
+	installed, err := setupBuildah(ctx, binDir, fixtureEnvContext(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if installed {
+		t.Errorf("expected the buildah in the PATH to be used")
+	}
+}
+
+func TestFetchStableKubectlRelease(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		failures      int
+		expected      string
+		expectedError bool
+	}{
+		{name: "release", content: "v1.30.2\n", expected: "v1.30.2"},
+		{name: "retried after errors", content: "v1.30.2", failures: 2, expected: "v1.30.2"},
+		{name: "retries exhausted", content: "v1.30.2", failures: 3, expectedError: true},
+		{name: "error page", content: "<html><body>Service Unavailable</body></html>", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			fixture.add(kubectlStableUrl, []byte(test.content))
+			fixture.fail(kubectlStableUrl, test.failures)
+
+			downloader := fixture.downloader()
+			downloader.RetryAttempts = 3
+
+			release, err := fetchStableKubectlRelease(withDownloader(context.Background(), downloader))
+			if test.expectedError {
+				if err == nil {
+					t.Fatalf("expected an error, got release: %s", release)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if release != test.expected {
+				t.Errorf("expected release %s, got %s", test.expected, release)
+			}
+		})
+	}
+}
+
+func TestSetupOperatorSdk(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	// the latest stable release is installed, skipping drafts and pre-releases
+	fixture.add("https://api.github.com/repos/operator-framework/operator-sdk/releases", []byte(`[
+		{"tag_name": "v1.36.0", "draft": true},
+		{"tag_name": "v1.35.0-rc1", "prerelease": true},
+		{"tag_name": "v1.34.1"},
+		{"tag_name": "v1.33.0"}
+	]`))
+
+	filename := fmt.Sprintf("operator-sdk_%s_%s", runtime.GOOS, runtime.GOARCH)
+	content := fixtureCli(`operator-sdk version: "v1.34.1", commit: "edaed1e", kubernetes version: "1.28.0"`)
+	fixture.add("https://github.com/operator-framework/operator-sdk/releases/download/v1.34.1/"+filename, content)
+	fixture.add("https://github.com/operator-framework/operator-sdk/releases/download/v1.34.1/checksums.txt", []byte(fixtureChecksum(content)+"  "+filename+"\n"))
+
+	installed, err := setupOperatorSdk(ctx, binDir, fixtureEnvContext(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !installed {
+		t.Errorf("expected operator-sdk to be installed")
+	}
+
+	installedContent, err := os.ReadFile(filepath.Join(binDir, "operator-sdk"))
+	if err != nil {
+		t.Fatalf("expected operator-sdk in bin_dir: %s", err.Error())
+	}
+	if string(installedContent) != string(content) {
+		t.Errorf("expected operator-sdk v1.34.1 to be installed")
+	}
+
+	installed, err = setupOperatorSdk(ctx, binDir, fixtureEnvContext(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if installed {
+		t.Errorf("expected the operator-sdk in bin_dir to be used")
+	}
+}
+
+func TestGetKustomizeUrl(t *testing.T) {
+	tests := []struct {
+		version  string
+		osName   string
+		arch     string
+		expected string
+	}{
+		{version: "5.4.3", osName: "linux", arch: "amd64", expected: "https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize%2Fv5.4.3/kustomize_v5.4.3_linux_amd64.tar.gz"},
+		{version: "v5.4.3", osName: "darwin", arch: "arm64", expected: "https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize%2Fv5.4.3/kustomize_v5.4.3_darwin_arm64.tar.gz"},
+		{version: "4.5.4", osName: "linux", arch: "arm64", expected: "https://github.com/kubernetes-sigs/kustomize/releases/download/kustomize%2Fv4.5.4/kustomize_v4.5.4_linux_arm64.tar.gz"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version+"-"+test.osName+"-"+test.arch, func(t *testing.T) {
+			if url := getKustomizeUrl(test.version, test.osName, test.arch); url != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, url)
+			}
+		})
+	}
+}
+
+func TestSetupKustomize(t *testing.T) {
+	fixture := newFixtureServer(t)
+	ctx := fixtureContext(t, fixture)
+	binDir := t.TempDir()
+
+	// the tag must reach the server with the slash still encoded
+	fixture.add(getKustomizeUrl("5.4.3", runtime.GOOS, runtime.GOARCH), fixtureTgz(t, map[string][]byte{
+		"kustomize": fixtureCli("v5.4.3"),
+	}))
+
+	installed, err := setupKustomize(ctx, binDir, fixtureEnvContext(), "5.4.3")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !installed {
+		t.Errorf("expected kustomize to be installed")
+	}
+}
+
+func TestCreateSymLink(t *testing.T) {
+	tests := []struct {
+		name            string
+		setup           func(t *testing.T, linkTo string)
+		expectedCreated bool
+		expectedTarget  bool
+	}{
+		{
+			name:            "missing link",
+			setup:           func(t *testing.T, linkTo string) {},
+			expectedCreated: true,
+			expectedTarget:  true,
+		},
+		{
+			name: "dangling link",
+			setup: func(t *testing.T, linkTo string) {
+				if err := os.Symlink(filepath.Join(t.TempDir(), "removed"), linkTo); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedCreated: true,
+			expectedTarget:  true,
+		},
+		{
+			name: "link to another cli",
+			setup: func(t *testing.T, linkTo string) {
+				other := filepath.Join(t.TempDir(), "mycli")
+				if err := os.WriteFile(other, fixtureCli("1.0.0"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Symlink(other, linkTo); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedCreated: true,
+			expectedTarget:  true,
+		},
+		{
+			name: "downloaded cli",
+			setup: func(t *testing.T, linkTo string) {
+				if err := os.WriteFile(linkTo, fixtureCli("1.0.0"), 0755); err != nil {
+					t.Fatal(err)
+				}
+			},
+			expectedCreated: false,
+			expectedTarget:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pathDir := t.TempDir()
+			cliPath := filepath.Join(pathDir, "mycli")
+			if err := os.WriteFile(cliPath, fixtureCli("2.0.0"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", pathDir)
+
+			linkTo := filepath.Join(t.TempDir(), "mycli")
+			test.setup(t, linkTo)
+
+			created, err := createSymLink("mycli", linkTo)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if created != test.expectedCreated {
+				t.Errorf("expected created to be %t", test.expectedCreated)
+			}
+
+			target, _ := os.Readlink(linkTo)
+			if (target == cliPath) != test.expectedTarget {
+				t.Errorf("unexpected link target: %s", target)
+			}
+
+			if _, err := os.Stat(linkTo); err != nil {
+				t.Errorf("expected the link to resolve: %s", err.Error())
+			}
+
+			if exists, _ := fileExists(linkTo + ".tmp"); exists {
+				t.Errorf("expected the temporary link to be removed")
+			}
+		})
+	}
+}
+
+func TestParseCliName(t *testing.T) {
+	tests := []struct {
+		cliName         string
+		expectedName    string
+		expectedVersion string
+	}{
+		{cliName: "argocd-2.11.0-rc1", expectedName: "argocd", expectedVersion: "2.11.0-rc1"},
+		{cliName: "tool-1.2.3+build", expectedName: "tool", expectedVersion: "1.2.3+build"},
+		{cliName: "s2i-1.3.0", expectedName: "s2i", expectedVersion: "1.3.0"},
+		{cliName: "argo-rollouts-1.6.6", expectedName: "argo-rollouts", expectedVersion: "1.6.6"},
+		{cliName: "operator-sdk@v1.34.1", expectedName: "operator-sdk", expectedVersion: "1.34.1"},
+		{cliName: "ibmcloud-ks", expectedName: "ibmcloud-ks", expectedVersion: ""},
+		{cliName: "jq", expectedName: "jq", expectedVersion: "1.6"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cliName, func(t *testing.T) {
+			name, version, err := parseCliName(test.cliName)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if name != test.expectedName || version != test.expectedVersion {
+				t.Errorf("expected %s, %s, got %s, %s", test.expectedName, test.expectedVersion, name, version)
+			}
+		})
+	}
+}
+
+func TestCleanVersionString(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "jq", value: "jq-1.7", expected: "1.7.0"},
+		{name: "helm", value: `version.BuildInfo{Version:"v3.14.0", GitCommit:"3fc9f4b2638e76f26739cd77c7017139be81d0ea", GitTreeState:"clean", GoVersion:"go1.21.5"}`, expected: "3.14.0"},
+		{name: "date", value: "2024.01.02", expected: "2024.1.2"},
+		{name: "name and version", value: "yamllint 1.35.1", expected: "1.35.1"},
+		{name: "single number", value: "hadolint 2", expected: "2.0.0"},
+		{name: "no version", value: "no version", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if version := cleanVersionString(test.value); version != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, version)
+			}
+		})
+	}
+}
+
+func TestParseCliVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		cliName  string
+		output   string
+		expected string
+	}{
+		{
+			name:     "oc client",
+			cliName:  "oc",
+			output:   "Client Version: 4.14.0-202310201027.p0.g0c63f9d.assembly.stream-0c63f9d\nKustomize Version: v5.0.1\n",
+			expected: "4.14.0",
+		},
+		{
+			name:     "oc client and server",
+			cliName:  "oc",
+			output:   "Client Version: 4.10.0-202203141248.p0.g6db43e2.assembly.stream-6db43e2\nServer Version: 4.12.5\nKubernetes Version: v1.25.4+18eadca\n",
+			expected: "4.10.0",
+		},
+		{
+			name:     "helm",
+			cliName:  "helm",
+			output:   `version.BuildInfo{Version:"v3.14.0", GitCommit:"3fc9f4b2638e76f26739cd77c7017139be81d0ea", GitTreeState:"clean", GoVersion:"go1.21.5"}` + "\n",
+			expected: "3.14.0",
+		},
+		{
+			name:     "kubectl",
+			cliName:  "kubectl",
+			output:   "Client Version: v1.28.2\nKustomize Version: v5.0.4-0.20230601165947-6ce0bf390ce3\n",
+			expected: "1.28.2",
+		},
+		{
+			name:     "kubectl version info",
+			cliName:  "kubectl",
+			output:   `Client Version: version.Info{Major:"1", Minor:"23", GitVersion:"v1.23.5", GitCommit:"c285e781331a3785a7f436042c65c5641ce8a9e9", GitTreeState:"clean", GoVersion:"go1.17.8", Compiler:"gc", Platform:"linux/amd64"}` + "\n",
+			expected: "1.23.5",
+		},
+		{
+			name:     "cli without a version expression",
+			cliName:  "jq",
+			output:   "jq-1.6\n",
+			expected: "1.6.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if version := parseCliVersion(test.cliName, test.output); version != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, version)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package clis
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataClisSupported() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataClisSupportedRead,
+		Schema: map[string]*schema.Schema{
+			"clis": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the cli, as used in the clis attribute of the clis_check data source.",
+						},
+						"default_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The version installed when no version is requested. Empty when the latest release is installed.",
+						},
+					},
+				},
+				Description: "The clis that can be installed by the clis_check data source, sorted by name.",
+			},
+		},
+	}
+}
+
+func dataClisSupportedRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	defaultVersions := getDefaultVersions()
+
+	names := supportedCliNames()
+
+	clis := make([]interface{}, len(names))
+	for i, name := range names {
+		clis[i] = map[string]interface{}{
+			"name":            name,
+			"default_version": defaultVersions[name],
+		}
+	}
+
+	if err := d.Set("clis", clis); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("clis_supported")
+
+	return diags
+}
@@ -0,0 +1,76 @@
+package clis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// orderClisByDependencies returns the clis ordered so that every cli comes after the clis it depends on. A
+// dependency that wasn't requested is added with its default version. The order of the requested clis is
+// otherwise kept
+func orderClisByDependencies(clis []string) ([]string, error) {
+	registry := getInstallerRegistry()
+
+	// a dependency is satisfied by the requested cli of the same name, whatever version was requested
+	requested := make(map[string]string)
+	for _, cliName := range clis {
+		if name, _, err := parseCliName(cliName); err == nil {
+			if _, ok := requested[name]; !ok {
+				requested[name] = cliName
+			}
+		}
+	}
+
+	result := make([]string, 0, len(clis))
+	visited := make(map[string]bool)
+	visiting := make(map[string]bool)
+
+	var visit func(cliName string, path []string) error
+	visit = func(cliName string, path []string) error {
+		name, _, err := parseCliName(cliName)
+		if err != nil {
+			// the error is reported when the cli is installed
+			result = append(result, cliName)
+			return nil
+		}
+
+		if visited[cliName] {
+			return nil
+		}
+
+		if visiting[name] {
+			return fmt.Errorf("circular dependency between clis: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		visiting[name] = true
+
+		if installer, ok := registry.Get(name); ok {
+			if dependent, ok := installer.(DependentInstaller); ok {
+				for _, dependency := range dependent.Dependencies() {
+					dependencyCli, ok := requested[dependency]
+					if !ok {
+						dependencyCli = dependency
+					}
+
+					if err := visit(dependencyCli, append(path, name)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		visiting[name] = false
+		visited[cliName] = true
+		result = append(result, cliName)
+
+		return nil
+	}
+
+	for _, cliName := range clis {
+		if err := visit(cliName, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,249 @@
+package clis
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// fixtureHostHeader passes the host of the original url to the fixture server
+const fixtureHostHeader = "X-Fixture-Host"
+
+// fixtureServer serves canned responses for the urls requested by the installers, keyed by host and path
+// (e.g. get.helm.sh/helm-v3.8.2-linux-amd64.tar.gz)
+type fixtureServer struct {
+	server *httptest.Server
+
+	lock     sync.Mutex
+	files    map[string][]byte
+	failures map[string]int
+	requests []string
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	t.Helper()
+
+	fixture := &fixtureServer{files: make(map[string][]byte), failures: make(map[string]int)}
+	fixture.server = httptest.NewServer(http.HandlerFunc(fixture.serveHTTP))
+	t.Cleanup(fixture.server.Close)
+
+	return fixture
+}
+
+// add serves the content for the url
+func (f *fixtureServer) add(url string, content []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.files[fixtureKey(url)] = content
+}
+
+// fail responds to the next count requests for the url with a 503
+func (f *fixtureServer) fail(url string, count int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.failures[fixtureKey(url)] = count
+}
+
+// requestCount returns the number of requests served, including those for missing files
+func (f *fixtureServer) requestCount() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return len(f.requests)
+}
+
+// downloader returns a Downloader that sends every request to the fixture server without retrying
+func (f *fixtureServer) downloader() *Downloader {
+	return &Downloader{Client: fixtureClient{server: f.server}, RetryAttempts: 1}
+}
+
+func (f *fixtureServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(fixtureHostHeader) + r.URL.EscapedPath()
+
+	f.lock.Lock()
+	f.requests = append(f.requests, r.Method+" "+key)
+	content, ok := f.files[key]
+	failing := f.failures[key] > 0
+	if failing {
+		f.failures[key]--
+	}
+	f.lock.Unlock()
+
+	if failing {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// ServeContent handles the HEAD and Range requests made by checkUrlExists and downloadWithResume
+	http.ServeContent(w, r, path.Base(r.URL.Path), time.Time{}, bytes.NewReader(content))
+}
+
+func fixtureKey(url string) string {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return req.URL.Host + req.URL.EscapedPath()
+}
+
+// fixtureClient rewrites the requests to the fixture server, passing the original host in a header
+type fixtureClient struct {
+	server *httptest.Server
+}
+
+func (c fixtureClient) Do(req *http.Request) (*http.Response, error) {
+	fixtureReq := req.Clone(req.Context())
+	fixtureReq.Header.Set(fixtureHostHeader, req.URL.Host)
+	fixtureReq.URL.Scheme = "http"
+	fixtureReq.URL.Host = c.server.Listener.Addr().String()
+	fixtureReq.Host = ""
+
+	return c.server.Client().Do(fixtureReq)
+}
+
+// fixtureCli returns a shell script that stands in for a cli by printing the output of its version command
+func fixtureCli(versionOutput string) []byte {
+	return []byte(fmt.Sprintf("#!/bin/sh\necho '%s'\n", versionOutput))
+}
+
+// fixtureTgz returns a gzipped tar archive of the executable files, keyed by their path in the archive
+func fixtureTgz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// fixtureZip returns a zip archive of the executable files, keyed by their path in the archive
+func fixtureZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		header.SetMode(0755)
+
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+// fixtureGz returns the gzipped content, as published for clis released as a single compressed binary
+func fixtureGz(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+
+	if _, err := gzipWriter.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func fixtureChecksum(content []byte) string {
+	checksum := sha256.Sum256(content)
+
+	return hex.EncodeToString(checksum[:])
+}
+
+// fixtureContext returns a context for calling the installers directly, with requests sent to the fixture server
+// and an empty PATH so that no cli on the machine running the tests is used
+func fixtureContext(t *testing.T, fixture *fixtureServer) context.Context {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture clis are shell scripts")
+	}
+
+	t.Setenv("PATH", t.TempDir())
+
+	return withDownloader(context.Background(), fixture.downloader())
+}
+
+// fixtureEnvContext returns the EnvContext of the platform running the tests
+func fixtureEnvContext() EnvContext {
+	return EnvContext{Os: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// readClisCheck runs the clis_check data source with the config, sending its requests to the fixture server
+func readClisCheck(t *testing.T, fixture *fixtureServer, binDir string, raw map[string]interface{}) (*schema.ResourceData, diag.Diagnostics) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture clis are shell scripts")
+	}
+
+	t.Setenv("PATH", t.TempDir())
+
+	config := &ProviderConfig{
+		BinDir:     binDir,
+		LinkMode:   linkModeSymlink,
+		EnvContext: fixtureEnvContext(),
+		Downloader: fixture.downloader(),
+	}
+
+	d := schema.TestResourceDataRaw(t, dataClisCheck().Schema, raw)
+
+	return d, dataClisCheckRead(context.Background(), d, config)
+}
@@ -0,0 +1,90 @@
+package clis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultGitLabHost = "gitlab.com"
+
+type GitLabRelease struct {
+	TagName         string              `json:"tag_name"`
+	UpcomingRelease bool                `json:"upcoming_release"`
+	Assets          GitLabReleaseAssets `json:"assets"`
+}
+
+type GitLabReleaseAssets struct {
+	Links []GitLabReleaseLink `json:"links"`
+}
+
+type GitLabReleaseLink struct {
+	Name           string `json:"name"`
+	Url            string `json:"url"`
+	DirectAssetUrl string `json:"direct_asset_url"`
+}
+
+// getLatestGitLabRelease returns the most recent release of the project (e.g. gitlab-org/cli) on the GitLab host,
+// skipping upcoming releases. The host defaults to gitlab.com
+func getLatestGitLabRelease(ctx context.Context, host string, project string) (*GitLabRelease, error) {
+	if len(host) == 0 {
+		host = defaultGitLabHost
+	}
+
+	// the project path is used in place of the numeric id so it has to be url encoded
+	url := fmt.Sprintf("https://%s/api/v4/projects/%s/releases", host, strings.Replace(project, "/", "%2F", -1))
+
+	resp, err := httpGetWithRetry(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if tmpError := resp.Body.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status retrieving releases from url: %s, %s", resp.Status, url)
+	}
+
+	var releases []GitLabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("unable to parse releases from url: %s, %w", url, err)
+	}
+
+	// releases are returned newest first
+	for _, release := range releases {
+		if release.UpcomingRelease {
+			continue
+		}
+
+		releaseInfo := release
+		return &releaseInfo, err
+	}
+
+	return nil, fmt.Errorf("unable to find release of %s on %s", project, host)
+}
+
+// getGitLabReleaseAssetUrl returns the name and download url of the first release asset that matches one of the
+// names, in order. Release assets on GitLab are links, which may point anywhere, so the url can't be derived from
+// the tag like it is for GitHub
+func getGitLabReleaseAssetUrl(release *GitLabRelease, assetNames ...string) (string, string, error) {
+	for _, assetName := range assetNames {
+		for _, link := range release.Assets.Links {
+			if link.Name != assetName {
+				continue
+			}
+
+			if len(link.DirectAssetUrl) > 0 {
+				return link.Name, link.DirectAssetUrl, nil
+			}
+
+			return link.Name, link.Url, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unable to find asset %s in release %s", strings.Join(assetNames, " or "), release.TagName)
+}
@@ -2,8 +2,16 @@ package clis
 
 import (
 	"bufio"
-	"log"
+	"context"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 func interfacesToString(list []interface{}) []string {
@@ -35,18 +43,132 @@ func unique(stringSlice []string) []string {
 	return list
 }
 
-func checkForAlpine() bool {
+func mapValues(values map[string]string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// checkBinDirWritable creates the bin_dir if necessary and verifies that files can be written to it
+func checkBinDirWritable(binDir string) error {
+	if err := os.MkdirAll(binDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(binDir, ".clis-write-check-")
+	if err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(file.Name())
+}
+
+type linkModeContextKey struct{}
+
+func withLinkMode(ctx context.Context, linkMode string) context.Context {
+	return context.WithValue(ctx, linkModeContextKey{}, linkMode)
+}
+
+func linkModeFromContext(ctx context.Context) string {
+	if linkMode, ok := ctx.Value(linkModeContextKey{}).(string); ok && len(linkMode) > 0 {
+		return linkMode
+	}
+
+	return linkModeSymlink
+}
+
+type pathModificationContextKey struct{}
+
+func withPathModification(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, pathModificationContextKey{}, enabled)
+}
+
+// pathModificationFromContext returns false if bin_dir has not been added to the PATH
+func pathModificationFromContext(ctx context.Context) bool {
+	if enabled, ok := ctx.Value(pathModificationContextKey{}).(bool); ok {
+		return enabled
+	}
+
+	return true
+}
+
+type crossInstallContextKey struct{}
+
+func withCrossInstall(ctx context.Context, crossInstall bool) context.Context {
+	return context.WithValue(ctx, crossInstallContextKey{}, crossInstall)
+}
+
+// crossInstallFromContext returns true if the clis are being installed for another platform, in which case
+// the clis in the PATH can't be reused and the downloaded clis can't be run
+func crossInstallFromContext(ctx context.Context) bool {
+	crossInstall, _ := ctx.Value(crossInstallContextKey{}).(bool)
+
+	return crossInstall
+}
+
+type checkOnlyContextKey struct{}
+
+func withCheckOnly(ctx context.Context, checkOnly bool) context.Context {
+	return context.WithValue(ctx, checkOnlyContextKey{}, checkOnly)
+}
+
+// checkOnlyFromContext returns true if the clis are only being checked, in which case nothing may be written to
+// bin_dir
+func checkOnlyFromContext(ctx context.Context) bool {
+	checkOnly, _ := ctx.Value(checkOnlyContextKey{}).(bool)
+
+	return checkOnly
+}
+
+// copyFile copies the contents of src, following any symlinks, to an executable file at dest
+func copyFile(src string, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if tmpError := in.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	out, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// checkForAlpine returns true if /etc/os-release identifies the distribution as alpine. Errors reading the file
+// are logged rather than failing the provider and alpine is assumed to be false
+func checkForAlpine(ctx context.Context) bool {
 	if exists, err := fileExists("/etc/os-release"); !exists || err != nil {
 		return false
 	}
 
 	file, err := os.Open("/etc/os-release")
 	if err != nil {
-		log.Fatal(err)
+		tflog.Warn(ctx, fmt.Sprintf("Unable to open /etc/os-release: %s", err.Error()))
+		return false
 	}
 	defer func() {
 		if tmpError := file.Close(); tmpError != nil {
-			log.Fatal(err)
+			tflog.Warn(ctx, fmt.Sprintf("Unable to close /etc/os-release: %s", tmpError.Error()))
 		}
 	}()
 
@@ -62,8 +184,27 @@ func checkForAlpine() bool {
 	}
 
 	if err := lines.Err(); err != nil {
-		log.Fatal(err)
+		tflog.Warn(ctx, fmt.Sprintf("Unable to read /etc/os-release: %s", err.Error()))
+		return false
 	}
 
 	return alpine
 }
+
+// checkForMusl looks for the musl dynamic linker, falling back to the output of ldd, to identify distributions
+// other than alpine that are built on musl
+func checkForMusl() bool {
+	if matches, err := filepath.Glob("/lib/ld-musl-*"); err == nil && len(matches) > 0 {
+		return true
+	}
+
+	lddPath, err := exec.LookPath("ldd")
+	if err != nil {
+		return false
+	}
+
+	// musl's ldd prints its version to stderr and exits with an error, so the error is ignored
+	out, _ := exec.Command(lddPath, "--version").CombinedOutput()
+
+	return strings.Contains(strings.ToLower(string(out)), "musl")
+}
@@ -0,0 +1,320 @@
+package clis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const progressReportInterval = 10 * 1024 * 1024
+
+// registryTokenHosts are the hosts the registry token is sent to. The http client drops the Authorization
+// header when a download is redirected to another host, so the token is not passed on to the storage backends
+var registryTokenHosts = []string{"github.com", "api.github.com"}
+
+// HttpClient is the part of *http.Client used to make requests, so that a client serving canned responses
+// (e.g. from an httptest.Server) can be provided instead
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Downloader holds the client and retry settings used for all requests made to look up releases and download clis
+type Downloader struct {
+	Client        HttpClient
+	RetryAttempts int
+	RetryDelay    time.Duration
+}
+
+// newDownloader creates a Downloader that honors the proxy environment variables (HTTPS_PROXY, NO_PROXY, etc).
+// Only connecting and waiting for the response headers are time limited, since downloading the larger clis
+// can take several minutes
+func newDownloader() *Downloader {
+	return &Downloader{
+		Client: &http.Client{
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				DialContext: (&net.Dialer{
+					Timeout:   30 * time.Second,
+					KeepAlive: 30 * time.Second,
+				}).DialContext,
+				TLSHandshakeTimeout:   10 * time.Second,
+				ResponseHeaderTimeout: 60 * time.Second,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConns:          10,
+			},
+		},
+		RetryAttempts: 3,
+		RetryDelay:    2 * time.Second,
+	}
+}
+
+var defaultDownloader = newDownloader()
+
+type downloaderContextKey struct{}
+
+func withDownloader(ctx context.Context, downloader *Downloader) context.Context {
+	return context.WithValue(ctx, downloaderContextKey{}, downloader)
+}
+
+func downloaderFromContext(ctx context.Context) *Downloader {
+	if downloader, ok := ctx.Value(downloaderContextKey{}).(*Downloader); ok && downloader != nil {
+		return downloader
+	}
+
+	return defaultDownloader
+}
+
+type registryTokenContextKey struct{}
+
+func withRegistryToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, registryTokenContextKey{}, token)
+}
+
+func registryTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(registryTokenContextKey{}).(string)
+
+	return token
+}
+
+// newHttpRequest creates a request for the url, authenticated with the registry token from the context if the
+// url is on one of the registryTokenHosts
+func newHttpRequest(ctx context.Context, method string, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if token := registryTokenFromContext(ctx); len(token) > 0 {
+		for _, host := range registryTokenHosts {
+			if req.URL.Host == host {
+				req.Header.Set("Authorization", "Bearer "+token)
+				break
+			}
+		}
+	}
+
+	return req, nil
+}
+
+// httpGet issues a GET request for the url. The caller is responsible for closing the body of the returned response.
+func httpGet(ctx context.Context, url string) (*http.Response, error) {
+	req, err := newHttpRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return downloaderFromContext(ctx).Client.Do(req)
+}
+
+// httpGetWithRetry issues a GET request for the url, retrying on connection errors and
+// 5xx responses. The caller is responsible for closing the body of the returned response.
+func httpGetWithRetry(ctx context.Context, url string) (*http.Response, error) {
+	downloader := downloaderFromContext(ctx)
+
+	var lastErr error
+
+	for attempt := 1; attempt <= downloader.RetryAttempts; attempt++ {
+		req, err := newHttpRequest(ctx, http.MethodGet, url)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := downloader.Client.Do(req)
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else {
+			lastErr = fmt.Errorf("bad status: %s", resp.Status)
+			_ = resp.Body.Close()
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("Attempt %d of %d to retrieve url failed: %s, %s", attempt, downloader.RetryAttempts, url, lastErr.Error()))
+
+		if attempt < downloader.RetryAttempts {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(downloader.RetryDelay * time.Duration(attempt)):
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unable to retrieve url after %d attempts: %s, %w", downloader.RetryAttempts, url, lastErr)
+}
+
+// downloadWithResume downloads the url to destPath, retrying on connection errors and 5xx responses. The partial
+// download is kept between attempts and, if the server advertises support for byte ranges, the download is
+// resumed from where the previous attempt stopped instead of starting again
+func downloadWithResume(ctx context.Context, url string, destPath string) error {
+	downloader := downloaderFromContext(ctx)
+	partialPath := destPath + ".partial"
+
+	var lastErr error
+	acceptRanges := false
+
+	for attempt := 1; attempt <= downloader.RetryAttempts; attempt++ {
+		if attempt > 1 {
+			tflog.Debug(ctx, fmt.Sprintf("Attempt %d of %d to download url failed: %s, %s", attempt-1, downloader.RetryAttempts, url, lastErr.Error()))
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(downloader.RetryDelay * time.Duration(attempt-1)):
+			}
+		}
+
+		done, acceptsRanges, err := downloadAttempt(ctx, url, partialPath, acceptRanges)
+		if done {
+			if err != nil {
+				_ = os.Remove(partialPath)
+				return err
+			}
+
+			return os.Rename(partialPath, destPath)
+		}
+
+		acceptRanges = acceptsRanges
+		lastErr = err
+	}
+
+	_ = os.Remove(partialPath)
+
+	return fmt.Errorf("unable to download url after %d attempts: %s, %w", downloader.RetryAttempts, url, lastErr)
+}
+
+// downloadAttempt appends the remainder of the url to partialPath, requesting only the missing bytes if
+// resume is true. The result is done if the download completed or failed with an error that should not be
+// retried, along with whether the server accepts byte ranges
+func downloadAttempt(ctx context.Context, url string, partialPath string, resume bool) (bool, bool, error) {
+	out, err := os.OpenFile(partialPath, os.O_WRONLY|os.O_CREATE, 0755)
+	if err != nil {
+		return true, false, err
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	offset := int64(0)
+	if resume {
+		if offset, err = out.Seek(0, io.SeekEnd); err != nil {
+			return true, false, err
+		}
+	}
+
+	req, err := newHttpRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return true, false, err
+	}
+
+	if offset > 0 {
+		tflog.Debug(ctx, fmt.Sprintf("Resuming download of url from byte %d: %s", offset, url))
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := downloaderFromContext(ctx).Client.Do(req)
+	if err != nil {
+		return false, false, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	acceptRanges := resp.Header.Get("Accept-Ranges") == "bytes"
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+	case resp.StatusCode == http.StatusOK:
+		// the server sent the whole file so any partial download is discarded
+		if err := out.Truncate(0); err != nil {
+			return true, false, err
+		}
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return true, false, err
+		}
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return false, acceptRanges, fmt.Errorf("bad status: %s", resp.Status)
+	default:
+		return true, false, fmt.Errorf("bad status retrieving url: %s, %s", resp.Status, url)
+	}
+
+	if _, err := io.Copy(out, newProgressReader(ctx, resp, url)); err != nil {
+		return false, acceptRanges, err
+	}
+
+	return true, acceptRanges, out.Close()
+}
+
+// checkUrlExists issues a HEAD request for the url so that a missing asset is reported before the download
+// is started. Servers that don't support HEAD requests are left to report any problem with the download itself.
+func checkUrlExists(ctx context.Context, url string) error {
+	req, err := newHttpRequest(ctx, http.MethodHead, url)
+	if err != nil {
+		return err
+	}
+
+	resp, err := downloaderFromContext(ctx).Client.Do(req)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Unable to check url before download: %s, %s", url, err.Error()))
+		return nil
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("asset not found at %s", url)
+	case http.StatusOK:
+		return nil
+	default:
+		tflog.Debug(ctx, fmt.Sprintf("Unexpected status checking url before download: %s, %s", url, resp.Status))
+		return nil
+	}
+}
+
+// progressReader logs the progress of a download every progressReportInterval bytes so that slow or hung
+// downloads can be diagnosed
+type progressReader struct {
+	ctx        context.Context
+	reader     io.Reader
+	url        string
+	total      int64
+	read       int64
+	nextReport int64
+}
+
+func newProgressReader(ctx context.Context, resp *http.Response, url string) io.Reader {
+	return &progressReader{
+		ctx:        ctx,
+		reader:     resp.Body,
+		url:        url,
+		total:      resp.ContentLength,
+		nextReport: progressReportInterval,
+	}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+
+	if r.read >= r.nextReport {
+		if r.total > 0 {
+			tflog.Debug(r.ctx, fmt.Sprintf("Downloaded %d of %d bytes (%d%%) from %s", r.read, r.total, r.read*100/r.total, r.url))
+		} else {
+			tflog.Debug(r.ctx, fmt.Sprintf("Downloaded %d bytes from %s", r.read, r.url))
+		}
+
+		for r.nextReport <= r.read {
+			r.nextReport += progressReportInterval
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,65 @@
+package clis
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestHttpGetWithRetry(t *testing.T) {
+	url := "https://example.com/release.txt"
+
+	tests := []struct {
+		name           string
+		failures       int
+		retryAttempts  int
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "success", failures: 0, retryAttempts: 3, expectedStatus: http.StatusOK, expectedCount: 1},
+		{name: "retried", failures: 2, retryAttempts: 3, expectedStatus: http.StatusOK, expectedCount: 3},
+		{name: "retries exhausted", failures: 3, retryAttempts: 3, expectedCount: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := newFixtureServer(t)
+			fixture.add(url, []byte("v1.30.0"))
+			fixture.fail(url, test.failures)
+
+			downloader := fixture.downloader()
+			downloader.RetryAttempts = test.retryAttempts
+
+			resp, err := httpGetWithRetry(withDownloader(context.Background(), downloader), url)
+
+			if count := fixture.requestCount(); count != test.expectedCount {
+				t.Errorf("expected %d requests, got %d", test.expectedCount, count)
+			}
+
+			if test.expectedStatus == 0 {
+				if err == nil {
+					_ = resp.Body.Close()
+					t.Fatalf("expected an error once the retries are exhausted")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+
+			if resp.StatusCode != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, resp.StatusCode)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "v1.30.0" {
+				t.Errorf("unexpected body: %s", string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package clis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const lockFileName = ".clis.lock"
+
+// LockedCli records the artifact that was installed for a cli
+type LockedCli struct {
+	Requested string `json:"requested,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Url       string `json:"url"`
+	Sha256    string `json:"sha256"`
+}
+
+// LockFile records the resolved artifacts of the clis installed in a bin_dir so that subsequent
+// installs download exactly the same files instead of resolving the latest release again
+type LockFile struct {
+	Clis map[string]LockedCli `json:"clis"`
+
+	path     string
+	lock     sync.Mutex
+	recorded map[string]LockedCli
+}
+
+type lockFileContextKey struct{}
+
+type requestedVersionContextKey struct{}
+
+func loadLockFile(binDir string) (*LockFile, error) {
+	lockFile := &LockFile{
+		Clis:     make(map[string]LockedCli),
+		path:     filepath.Join(binDir, lockFileName),
+		recorded: make(map[string]LockedCli),
+	}
+
+	cliMutexKV.Lock(lockFile.path)
+	defer cliMutexKV.Unlock(lockFile.path)
+
+	if err := lockFile.read(); err != nil {
+		return nil, err
+	}
+
+	return lockFile, nil
+}
+
+func (l *LockFile) read() error {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, l); err != nil {
+		return fmt.Errorf("unable to parse lock file %s: %w", l.path, err)
+	}
+
+	if l.Clis == nil {
+		l.Clis = make(map[string]LockedCli)
+	}
+
+	return nil
+}
+
+// save merges the clis recorded during this run into the lock file on disk
+func (l *LockFile) save() error {
+	if l == nil {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.recorded) == 0 {
+		return nil
+	}
+
+	cliMutexKV.Lock(l.path)
+	defer cliMutexKV.Unlock(l.path)
+
+	// another data source may have updated the lock file in the meantime
+	if err := l.read(); err != nil {
+		return err
+	}
+
+	for cliName, lockedCli := range l.recorded {
+		l.Clis[cliName] = lockedCli
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(l.path, data, 0644)
+}
+
+// lockedVersion returns the version recorded for the cli if it was installed for the same requested version
+func (l *LockFile) lockedVersion(cliName string, requested string) (string, bool) {
+	lockedCli, ok := l.get(cliName)
+	if !ok || lockedCli.Requested != requested || len(lockedCli.Version) == 0 {
+		return "", false
+	}
+
+	return lockedCli.Version, true
+}
+
+// lockedUrl returns the url recorded for the cli if it was installed for the same requested version,
+// otherwise the provided url
+func (l *LockFile) lockedUrl(ctx context.Context, cliName string, requested string, url string) string {
+	requested = requestedVersionFromContext(ctx, requested)
+
+	lockedCli, ok := l.get(cliName)
+	if !ok || lockedCli.Requested != requested || len(lockedCli.Url) == 0 {
+		return url
+	}
+
+	if lockedCli.Url != url {
+		tflog.Debug(ctx, fmt.Sprintf("Using url from lock file for cli: %s, %s", cliName, lockedCli.Url))
+	}
+
+	return lockedCli.Url
+}
+
+// verify checks the checksum of a download against the lock file entry for the same url
+func (l *LockFile) verify(cliName string, url string, checksum string) error {
+	lockedCli, ok := l.get(cliName)
+	if !ok || lockedCli.Url != url || len(lockedCli.Sha256) == 0 {
+		return nil
+	}
+
+	if lockedCli.Sha256 != checksum {
+		return fmt.Errorf("checksum of downloaded cli %s does not match the lock file: %s != %s", cliName, checksum, lockedCli.Sha256)
+	}
+
+	return nil
+}
+
+func (l *LockFile) record(cliName string, lockedCli LockedCli) {
+	if l == nil {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.recorded[cliName] = lockedCli
+}
+
+func (l *LockFile) get(cliName string) (LockedCli, bool) {
+	if l == nil {
+		return LockedCli{}, false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	lockedCli, ok := l.Clis[cliName]
+
+	return lockedCli, ok
+}
+
+// entry returns the lock file entry for the cli, preferring the one recorded during this run
+func (l *LockFile) entry(cliName string) (LockedCli, bool) {
+	if l == nil {
+		return LockedCli{}, false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if lockedCli, ok := l.recorded[cliName]; ok {
+		return lockedCli, ok
+	}
+
+	lockedCli, ok := l.Clis[cliName]
+
+	return lockedCli, ok
+}
+
+func withLockFile(ctx context.Context, lockFile *LockFile) context.Context {
+	return context.WithValue(ctx, lockFileContextKey{}, lockFile)
+}
+
+func lockFileFromContext(ctx context.Context) *LockFile {
+	lockFile, _ := ctx.Value(lockFileContextKey{}).(*LockFile)
+
+	return lockFile
+}
+
+// withRequestedVersion records the version the user asked for when the installer is given the version from the
+// lock file instead, so that the lock file entry keeps matching the request
+func withRequestedVersion(ctx context.Context, requested string) context.Context {
+	return context.WithValue(ctx, requestedVersionContextKey{}, requested)
+}
+
+func requestedVersionFromContext(ctx context.Context, version string) string {
+	if requested, ok := ctx.Value(requestedVersionContextKey{}).(string); ok {
+		return requested
+	}
+
+	return version
+}
+
+// recordInstalledCli adds the downloaded cli to the lock file in the context, if there is one
+func recordInstalledCli(ctx context.Context, destDir string, cliName string, requested string, url string, checksum string) {
+	lockFile := lockFileFromContext(ctx)
+	if lockFile == nil {
+		return
+	}
+
+	lockFile.record(cliName, LockedCli{
+		Requested: requestedVersionFromContext(ctx, requested),
+		Version:   getCliVersion(ctx, filepath.Join(destDir, cliName), cliName),
+		Url:       url,
+		Sha256:    checksum,
+	})
+}
+
+func getCliVersion(ctx context.Context, cliPath string, cliName string) string {
+	out, err := exec.Command(cliPath, getVersionArgs(cliName)...).Output()
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("Unable to get version of cli: %s, %s", cliName, err.Error()))
+		return ""
+	}
+
+	return parseCliVersion(cliName, string(out))
+}
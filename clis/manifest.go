@@ -0,0 +1,60 @@
+package clis
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// ManifestEntry describes the outcome of the install for one of the requested clis
+type ManifestEntry struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Url       string `json:"url,omitempty"`
+}
+
+// Manifest describes the clis provided in a bin_dir by a read of the clis_check data source
+type Manifest struct {
+	BinDir string          `json:"bin_dir"`
+	Clis   []ManifestEntry `json:"clis"`
+}
+
+func newManifestEntry(ctx context.Context, binDir string, cliName string, installed bool) ManifestEntry {
+	name, _, err := parseCliName(cliName)
+	if err != nil {
+		name = cliName
+	}
+
+	entry := ManifestEntry{
+		Name:      name,
+		Installed: installed,
+	}
+
+	binary := getCliBinary(name)
+	if cliPath, err := lookPathInBinDir(ctx, binDir, binary); err == nil {
+		entry.Path = cliPath
+		entry.Version = getCliVersion(ctx, cliPath, name)
+	}
+
+	if lockedCli, ok := lockFileFromContext(ctx).entry(binary); ok {
+		entry.Url = lockedCli.Url
+	}
+
+	return entry
+}
+
+func writeManifest(filename string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0644)
+}
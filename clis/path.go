@@ -0,0 +1,97 @@
+package clis
+
+import (
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+var pathLock sync.Mutex
+
+// originalPath is the PATH of the provider process before any bin_dir was added
+var originalPath string
+
+// pathBinDirs are the bin_dirs of the reads in progress, most recently added first, with the number of reads
+// using each of them
+var pathBinDirs []string
+var pathBinDirReads = make(map[string]int)
+
+// addBinDirToPath adds bin_dir to the front of the PATH for the duration of a read. The returned function
+// removes it again once the read is complete, restoring the original PATH after the last read. Reads that
+// share a bin_dir only add it once
+func addBinDirToPath(binDir string) (func(), error) {
+	if len(binDir) == 0 {
+		return func() {}, nil
+	}
+
+	if !strings.HasPrefix(binDir, "/") {
+		cwd, err := os.Getwd()
+		if err != nil {
+			cwd = "."
+		}
+
+		binDir = path.Join(cwd, binDir)
+	}
+
+	pathLock.Lock()
+	defer pathLock.Unlock()
+
+	if len(pathBinDirReads) == 0 {
+		originalPath = os.Getenv("PATH")
+	}
+
+	if pathBinDirReads[binDir] == 0 {
+		pathBinDirs = append([]string{binDir}, pathBinDirs...)
+	}
+	pathBinDirReads[binDir]++
+
+	if err := setPath(); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		pathLock.Lock()
+		defer pathLock.Unlock()
+
+		pathBinDirReads[binDir]--
+		if pathBinDirReads[binDir] == 0 {
+			delete(pathBinDirReads, binDir)
+
+			for i, dir := range pathBinDirs {
+				if dir == binDir {
+					pathBinDirs = append(pathBinDirs[:i], pathBinDirs[i+1:]...)
+					break
+				}
+			}
+		}
+
+		_ = setPath()
+	}, nil
+}
+
+// setPath sets the PATH to the bin_dirs in use followed by the original PATH. The caller must hold pathLock
+func setPath() error {
+	value := originalPath
+	for i := len(pathBinDirs) - 1; i >= 0; i-- {
+		value = prependPathEntry(value, pathBinDirs[i])
+	}
+
+	return os.Setenv("PATH", value)
+}
+
+// prependPathEntry makes dir the first entry of the path value. The value is returned unchanged if dir is already
+// the first entry, otherwise any other occurrences of dir are removed along with duplicate entries so that
+// exec.LookPath gives the same result regardless of how often a bin_dir has been added
+func prependPathEntry(value string, dir string) string {
+	if len(value) == 0 {
+		return dir
+	}
+
+	entries := strings.Split(value, ":")
+	if entries[0] == dir {
+		return value
+	}
+
+	return strings.Join(unique(append([]string{dir}, entries...)), ":")
+}
@@ -4,6 +4,7 @@ import (
 	context "context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"regexp"
 	"runtime"
 	mutexkv "terraform-provider-clis/mutex"
@@ -11,6 +12,13 @@ import (
 
 var cliMutexKV = mutexkv.NewMutexKV()
 
+const defaultBinDir = "bin"
+
+const linkModeSymlink = "symlink"
+const linkModeCopy = "copy"
+
+var defaultClis = []string{"yq", "jq", "igc", "kubeseal", "oc", "kubectl"}
+
 var armArch = regexp.MustCompile(`^arm`)
 var macos = regexp.MustCompile(`darwin`)
 
@@ -18,6 +26,7 @@ type EnvContext struct {
 	Arch   string
 	Os     string
 	Alpine bool
+	Musl   bool
 }
 
 func (c EnvContext) isArmArch() bool {
@@ -32,6 +41,20 @@ func (c EnvContext) isAlpine() bool {
 	return c.Alpine
 }
 
+// isCrossInstall returns true if the clis are installed for a platform other than the one the provider runs on
+func (c EnvContext) isCrossInstall() bool {
+	return c.Os != runtime.GOOS || c.Arch != runtime.GOARCH
+}
+
+func (c EnvContext) isFreeBSD() bool {
+	return c.Os == "freebsd"
+}
+
+// isMusl returns true if the libc is musl rather than glibc, in which case the static builds of the clis are required
+func (c EnvContext) isMusl() bool {
+	return c.Alpine || c.Musl
+}
+
 // Provider -
 func Provider() *schema.Provider {
 	return &schema.Provider{
@@ -40,34 +63,97 @@ func Provider() *schema.Provider {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "The directory where the clis should be installed.",
-				Default:     "bin",
+				Default:     defaultBinDir,
+			},
+			"link_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      linkModeSymlink,
+				ValidateFunc: validation.StringInSlice([]string{linkModeSymlink, linkModeCopy}, false),
+				Description:  "How clis that are already available in the PATH are provided in bin_dir. Either symlink or copy. Use copy when bin_dir needs to be self-contained.",
+			},
+			"registry_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("CLIS_REGISTRY_TOKEN", nil),
+				Description: "The token used to authenticate the requests made to GitHub to look up releases and download clis, e.g. for private releases or to avoid rate limits. Can also be set with the CLIS_REGISTRY_TOKEN environment variable. Provider configuration is not stored in the state.",
+			},
+			"target_os": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"linux", "darwin", "freebsd"}, false),
+				Description:  "The operating system the clis are installed for. Defaults to the operating system the provider runs on. The downloaded clis are not run to validate them when installing for another platform and the ibmcloud and helm plugins can only be installed for the platform the provider runs on.",
+			},
+			"target_arch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"amd64", "arm64"}, false),
+				Description:  "The architecture the clis are installed for. Defaults to the architecture the provider runs on. The downloaded clis are not run to validate them when installing for another platform.",
+			},
+			"default_clis": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The list of clis that are installed by every clis_check data source in addition to the clis it requests. Defaults to yq, jq, igc, kubeseal, oc and kubectl.",
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{},
 		DataSourcesMap: map[string]*schema.Resource{
-			"clis_check": dataClisCheck(),
+			"clis_check":     dataClisCheck(),
+			"clis_supported": dataClisSupported(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 type ProviderConfig struct {
-	BinDir     string
-	EnvContext EnvContext
+	BinDir        string
+	DefaultClis   []string
+	LinkMode      string
+	RegistryToken string
+	EnvContext    EnvContext
+	Downloader    *Downloader
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	binDir := d.Get("bin_dir").(string)
+	if len(binDir) == 0 {
+		// an explicitly empty bin_dir would otherwise install the clis into the working directory
+		binDir = defaultBinDir
+	}
+
+	providerDefaultClis := defaultClis
+	if value, ok := d.GetOk("default_clis"); ok {
+		providerDefaultClis = interfacesToString(value.([]interface{}))
+	}
+
+	targetOs := runtime.GOOS
+	if value, ok := d.GetOk("target_os"); ok {
+		targetOs = value.(string)
+	}
+
+	targetArch := runtime.GOARCH
+	if value, ok := d.GetOk("target_arch"); ok {
+		targetArch = value.(string)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
 	c := &ProviderConfig{
-		BinDir: binDir,
+		BinDir:        binDir,
+		DefaultClis:   providerDefaultClis,
+		LinkMode:      d.Get("link_mode").(string),
+		RegistryToken: d.Get("registry_token").(string),
+		Downloader:    newDownloader(),
 		EnvContext: EnvContext{
-			Arch:   runtime.GOARCH,
-			Os:     runtime.GOOS,
-			Alpine: checkForAlpine(),
+			Arch:   targetArch,
+			Os:     targetOs,
+			Alpine: checkForAlpine(ctx),
+			Musl:   checkForMusl(),
 		},
 	}
 
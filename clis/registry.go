@@ -0,0 +1,147 @@
+package clis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Installer installs a single cli into a bin_dir. Install returns true when the cli was downloaded and false
+// when a suitable copy was already present
+type Installer interface {
+	Name() string
+	Install(ctx context.Context, binDir string, envContext EnvContext, version string) (bool, error)
+	// VersionCommand returns the arguments passed to the cli to print its version
+	VersionCommand() []string
+}
+
+// PresenceChecker is implemented by installers that provide something other than a single binary named after
+// the cli, so that the presence of the cli can be checked without installing it
+type PresenceChecker interface {
+	Present(ctx context.Context, binDir string, version string) bool
+}
+
+// DependentInstaller is implemented by installers that need other clis to be installed first (e.g. plugins that
+// are installed with their parent cli)
+type DependentInstaller interface {
+	Dependencies() []string
+}
+
+// InstallerRegistry holds the installers that can be used by the clis_check data source, keyed by cli name
+type InstallerRegistry struct {
+	lock       sync.RWMutex
+	installers map[string]Installer
+}
+
+func NewInstallerRegistry() *InstallerRegistry {
+	return &InstallerRegistry{installers: make(map[string]Installer)}
+}
+
+// Register adds the installer to the registry. An error is returned if an installer with the same name has
+// already been registered
+func (r *InstallerRegistry) Register(installer Installer) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	name := installer.Name()
+	if len(name) == 0 {
+		return fmt.Errorf("installer name is required")
+	}
+
+	if _, ok := r.installers[name]; ok {
+		return fmt.Errorf("installer already registered for cli: %s", name)
+	}
+
+	r.installers[name] = installer
+
+	return nil
+}
+
+func (r *InstallerRegistry) Get(name string) (Installer, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	installer, ok := r.installers[name]
+
+	return installer, ok
+}
+
+// Names returns the sorted names of the registered installers
+func (r *InstallerRegistry) Names() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	names := make([]string, 0, len(r.installers))
+	for name := range r.installers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+var installerRegistry *InstallerRegistry
+var installerRegistryOnce sync.Once
+
+// getInstallerRegistry returns the registry of installers, seeded with the built-in installers from getInstallers()
+func getInstallerRegistry() *InstallerRegistry {
+	installerRegistryOnce.Do(func() {
+		installerRegistry = NewInstallerRegistry()
+
+		for name, installer := range getInstallers() {
+			// the names in the map are unique so this can't fail
+			_ = installerRegistry.Register(cliInstallerAdapter{name: name, installer: installer})
+		}
+	})
+
+	return installerRegistry
+}
+
+// RegisterInstaller adds an installer to the registry used by the provider
+func RegisterInstaller(installer Installer) error {
+	return getInstallerRegistry().Register(installer)
+}
+
+// cliInstallerAdapter exposes one of the built-in CliInstaller entries as an Installer
+type cliInstallerAdapter struct {
+	name      string
+	installer CliInstaller
+}
+
+func (a cliInstallerAdapter) Name() string {
+	return a.name
+}
+
+func (a cliInstallerAdapter) Install(ctx context.Context, binDir string, envContext EnvContext, version string) (bool, error) {
+	if envContext.isFreeBSD() && !a.installer.FreeBSD {
+		return false, fmt.Errorf("no freebsd build is available for cli: %s", a.name)
+	}
+
+	return a.installer.Setup(ctx, binDir, envContext, version)
+}
+
+func (a cliInstallerAdapter) VersionCommand() []string {
+	if len(a.installer.VersionArgs) > 0 {
+		return a.installer.VersionArgs
+	}
+
+	return []string{"--version"}
+}
+
+func (a cliInstallerAdapter) Present(ctx context.Context, binDir string, version string) bool {
+	if a.installer.Present != nil {
+		return a.installer.Present(ctx, binDir, version)
+	}
+
+	if len(a.installer.Binary) > 0 {
+		return cliAlreadyPresent(ctx, binDir, a.installer.Binary, version)
+	}
+
+	return cliAlreadyPresent(ctx, binDir, a.name, version)
+}
+
+func (a cliInstallerAdapter) Dependencies() []string {
+	return a.installer.Dependencies
+}
@@ -0,0 +1,68 @@
+package clis
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readToolVersionsFile parses an asdf .tool-versions file into a map of tool name to version. Only the
+// first version listed for a tool is used and non-numeric versions (e.g. system, ref:...) are ignored
+func readToolVersionsFile(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open tool versions file %s: %w", filename, err)
+	}
+	defer func() {
+		if tmpError := file.Close(); tmpError != nil {
+			err = tmpError
+		}
+	}()
+
+	toolVersions := make(map[string]string)
+
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		line := lines.Text()
+		if index := strings.Index(line, "#"); index >= 0 {
+			line = line[:index]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		version := strings.TrimPrefix(fields[1], "v")
+		if len(version) == 0 || version[0] < '0' || version[0] > '9' {
+			continue
+		}
+
+		toolVersions[fields[0]] = version
+	}
+
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read tool versions file %s: %w", filename, err)
+	}
+
+	return toolVersions, nil
+}
+
+// applyToolVersions adds the version from the tool versions to each cli that has not been given an explicit version
+func applyToolVersions(clis []string, toolVersions map[string]string) []string {
+	result := make([]string, len(clis))
+	for i, cliName := range clis {
+		result[i] = cliName
+
+		if strings.Contains(cliName, "@") || versionedInstallRe.MatchString(cliName) {
+			continue
+		}
+
+		if version, ok := toolVersions[cliName]; ok {
+			result[i] = fmt.Sprintf("%s-%s", cliName, version)
+		}
+	}
+
+	return result
+}